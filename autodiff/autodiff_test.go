@@ -0,0 +1,198 @@
+package autodiff_test
+
+import (
+	"github.com/rocas777/kairos/autodiff"
+	"math"
+	"strings"
+	"testing"
+)
+
+func check(got, real float64, t *testing.T) {
+	if math.Abs(got-real) > 1e-12*math.Max(1, math.Abs(real)) {
+		t.Fatalf("Got: %.15f, wanted: %.15f", got, real)
+	}
+}
+
+func TestDual(t *testing.T) {
+	x := 0.7
+
+	tests := []struct {
+		name string
+		f    func(x autodiff.Dual) autodiff.Dual
+		fx   func(x float64) float64
+		dxF  func(x float64) float64
+	}{
+		{"polynomial", func(x autodiff.Dual) autodiff.Dual { return x.Mul(x).Mul(x).Sub(x.Scale(2)).Shift(1) },
+			func(x float64) float64 { return x*x*x - 2*x + 1 }, func(x float64) float64 { return 3*x*x - 2 }},
+		{"quotient", func(x autodiff.Dual) autodiff.Dual { return autodiff.Constant(1).Div(x.Shift(1)) },
+			func(x float64) float64 { return 1 / (x + 1) }, func(x float64) float64 { return -1 / ((x + 1) * (x + 1)) }},
+		{"neg", func(x autodiff.Dual) autodiff.Dual { return x.Neg() },
+			func(x float64) float64 { return -x }, func(x float64) float64 { return -1 }},
+		{"sin", autodiff.Dual.Sin, math.Sin, math.Cos},
+		{"cos", autodiff.Dual.Cos, math.Cos, func(x float64) float64 { return -math.Sin(x) }},
+		{"tan", autodiff.Dual.Tan, math.Tan, func(x float64) float64 { return 1 + math.Tan(x)*math.Tan(x) }},
+		{"asin", autodiff.Dual.Asin, math.Asin, func(x float64) float64 { return 1 / math.Sqrt(1-x*x) }},
+		{"acos", autodiff.Dual.Acos, math.Acos, func(x float64) float64 { return -1 / math.Sqrt(1-x*x) }},
+		{"atan", autodiff.Dual.Atan, math.Atan, func(x float64) float64 { return 1 / (1 + x*x) }},
+		{"sinh", autodiff.Dual.Sinh, math.Sinh, math.Cosh},
+		{"cosh", autodiff.Dual.Cosh, math.Cosh, math.Sinh},
+		{"tanh", autodiff.Dual.Tanh, math.Tanh, func(x float64) float64 { return 1 / (math.Cosh(x) * math.Cosh(x)) }},
+		{"exp", autodiff.Dual.Exp, math.Exp, math.Exp},
+		{"log", autodiff.Dual.Log, math.Log, func(x float64) float64 { return 1 / x }},
+		{"sqrt", autodiff.Dual.Sqrt, math.Sqrt, func(x float64) float64 { return 0.5 / math.Sqrt(x) }},
+		{"abs", func(x autodiff.Dual) autodiff.Dual { return x.Neg().Abs() },
+			func(x float64) float64 { return math.Abs(-x) }, func(x float64) float64 { return 1 }},
+		{"pow", func(x autodiff.Dual) autodiff.Dual { return x.Pow(2.5) },
+			func(x float64) float64 { return math.Pow(x, 2.5) }, func(x float64) float64 { return 2.5 * math.Pow(x, 1.5) }},
+		{"pow_dual", func(x autodiff.Dual) autodiff.Dual { return x.PowDual(x) },
+			func(x float64) float64 { return math.Pow(x, x) }, func(x float64) float64 { return math.Pow(x, x) * (math.Log(x) + 1) }},
+		{"composition", func(x autodiff.Dual) autodiff.Dual { return x.Mul(x).Sin().Exp() },
+			func(x float64) float64 { return math.Exp(math.Sin(x * x)) },
+			func(x float64) float64 { return math.Exp(math.Sin(x*x)) * math.Cos(x*x) * 2 * x }},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			value, derivative := autodiff.Evaluate(test.f, x)
+			check(value, test.fx(x), t)
+			check(derivative, test.dxF(x), t)
+			check(autodiff.Derivative(test.f, x), test.dxF(x), t)
+
+			fx, dxF := autodiff.Split(test.f)
+			check(fx(x), test.fx(x), t)
+			check(dxF(x), test.dxF(x), t)
+		})
+	}
+}
+
+func TestTape(t *testing.T) {
+	x := 0.7
+
+	// every operation on the tape should agree with the same operation on dual numbers
+	tests := []struct {
+		name  string
+		fDual func(x autodiff.Dual) autodiff.Dual
+		fVar  func(x autodiff.Var) autodiff.Var
+	}{
+		{"polynomial", func(x autodiff.Dual) autodiff.Dual { return x.Mul(x).Mul(x).Sub(x.Scale(2)).Shift(1) },
+			func(x autodiff.Var) autodiff.Var { return x.Mul(x).Mul(x).Sub(x.Scale(2)).Shift(1) }},
+		{"quotient", func(x autodiff.Dual) autodiff.Dual { return autodiff.Constant(1).Div(x.Shift(1)) },
+			func(x autodiff.Var) autodiff.Var { return x.Shift(1).Pow(-1) }},
+		{"neg", autodiff.Dual.Neg, autodiff.Var.Neg},
+		{"sin", autodiff.Dual.Sin, autodiff.Var.Sin},
+		{"cos", autodiff.Dual.Cos, autodiff.Var.Cos},
+		{"tan", autodiff.Dual.Tan, autodiff.Var.Tan},
+		{"asin", autodiff.Dual.Asin, autodiff.Var.Asin},
+		{"acos", autodiff.Dual.Acos, autodiff.Var.Acos},
+		{"atan", autodiff.Dual.Atan, autodiff.Var.Atan},
+		{"sinh", autodiff.Dual.Sinh, autodiff.Var.Sinh},
+		{"cosh", autodiff.Dual.Cosh, autodiff.Var.Cosh},
+		{"tanh", autodiff.Dual.Tanh, autodiff.Var.Tanh},
+		{"exp", autodiff.Dual.Exp, autodiff.Var.Exp},
+		{"log", autodiff.Dual.Log, autodiff.Var.Log},
+		{"sqrt", autodiff.Dual.Sqrt, autodiff.Var.Sqrt},
+		{"abs", func(x autodiff.Dual) autodiff.Dual { return x.Neg().Abs() },
+			func(x autodiff.Var) autodiff.Var { return x.Neg().Abs() }},
+		{"pow_var", func(x autodiff.Dual) autodiff.Dual { return x.PowDual(x) },
+			func(x autodiff.Var) autodiff.Var { return x.PowVar(x) }},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			value, gradient := autodiff.Gradient(func(x []autodiff.Var) autodiff.Var { return test.fVar(x[0]) }, []float64{x})
+			wantValue, wantDerivative := autodiff.Evaluate(test.fDual, x)
+			check(value, wantValue, t)
+			check(gradient[0], wantDerivative, t)
+		})
+	}
+}
+
+func TestTapeGradient(t *testing.T) {
+	rosenbrock := func(x []autodiff.Var) autodiff.Var {
+		out := x[0].Scale(0)
+		for i := 0; i+1 < len(x); i++ {
+			a := x[i+1].Sub(x[i].Mul(x[i]))
+			b := x[i].Neg().Shift(1)
+			out = out.Add(a.Mul(a).Scale(100)).Add(b.Mul(b))
+		}
+		return out
+	}
+	want := func(x []float64) []float64 {
+		g := make([]float64, len(x))
+		for i := 0; i+1 < len(x); i++ {
+			g[i] += -400*x[i]*(x[i+1]-x[i]*x[i]) - 2*(1-x[i])
+			g[i+1] += 200 * (x[i+1] - x[i]*x[i])
+		}
+		return g
+	}
+
+	tape := autodiff.NewTape()
+	var length int
+	for run, x := range [][]float64{{-1.2, 1, 0.5, 2}, {0.3, -0.4, 1.1, 0.9}} {
+		_, gradient := tape.Gradient(rosenbrock, x)
+		for i, g := range want(x) {
+			check(gradient[i], g, t)
+		}
+		// the same function records the same number of operations on a reused tape
+		if run > 0 && tape.Len() != length {
+			t.Fatalf("Recorded %d operations, wanted %d", tape.Len(), length)
+		}
+		length = tape.Len()
+	}
+
+	fx, gradient := autodiff.SplitGradient(rosenbrock)
+	x := []float64{1, 1, 1, 1}
+	check(fx(x), 0, t)
+	for _, g := range gradient(x) {
+		check(g, 0, t)
+	}
+
+	t.Run("constant", func(t *testing.T) {
+		tape := autodiff.NewTape()
+		x := tape.Variable(2)
+		y := x.Mul(tape.Constant(3))
+		if gradient := tape.Backward(y); len(gradient) != 1 || gradient[0] != 3 {
+			t.Fatalf("Got gradient %v, wanted [3]", gradient)
+		}
+	})
+
+	t.Run("different_tapes", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("Combining values of different tapes should panic")
+			}
+		}()
+		autodiff.NewTape().Variable(1).Add(autodiff.NewTape().Variable(2))
+	})
+
+	t.Run("invalid_values", func(t *testing.T) {
+		tests := []struct {
+			name string
+			use  func()
+		}{
+			{"unrecorded_operation", func() { autodiff.Var{}.Sin() }},
+			{"unrecorded_backward", func() { autodiff.NewTape().Backward(autodiff.Var{}) }},
+			{"reset_operation", func() {
+				tape := autodiff.NewTape()
+				x := tape.Variable(1)
+				tape.Reset()
+				x.Exp()
+			}},
+			{"reset_backward", func() {
+				tape := autodiff.NewTape()
+				y := tape.Variable(1).Exp().Exp()
+				tape.Reset()
+				tape.Variable(2)
+				tape.Backward(y)
+			}},
+		}
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				defer func() {
+					if r, ok := recover().(string); !ok || !strings.HasPrefix(r, "autodiff: ") {
+						t.Fatalf("Got panic %v, wanted an autodiff message", r)
+					}
+				}()
+				test.use()
+			})
+		}
+	})
+}
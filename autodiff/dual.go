@@ -0,0 +1,185 @@
+// Package autodiff provides automatic differentiation, which yields exact derivatives, free of the truncation and roundoff errors
+// of finite differences. Forward mode uses [dual numbers]: a function of one variable written against the [Dual] type, using its methods,
+// yields its derivative along with its value. Reverse mode records the operations made on [Var] values on a [Tape], and computes
+// the gradient of a function of many inputs in a single backward pass. Both types offer the same methods, from x.Add(y) to x.Sin(),
+// with [Dual.PowDual] and [Var.PowVar] raising a value to another one, so a function written for one of them reads the same for the other.
+//   - [Dual] numbers, with [Variable] and [Constant] to build them
+//   - [Derivative] and [Evaluate] to differentiate a function at a point
+//   - [Split] to obtain a function and its derivative as plain float64 functions, as expected by equation.NewtonRaphson
+//   - [Tape] and [Var] to record operations, with [Tape.Gradient] and [Gradient] to compute gradients
+//   - [SplitGradient] to obtain a multivariate function and its gradient as plain float64 functions
+//
+// [dual numbers]: https://en.wikipedia.org/wiki/Automatic_differentiation#Automatic_differentiation_using_dual_numbers
+package autodiff
+
+import "math"
+
+// Dual is a dual number a + b·ε with ε² = 0. 'Value' holds a and 'Derivative' holds b, which is carried through every operation
+// following the rules of differentiation, so evaluating a function at [Variable](x) gives f(x) and f'(x) at once.
+type Dual struct {
+	Value      float64
+	Derivative float64
+}
+
+// Variable returns the dual number of the independent variable at 'x', whose derivative is 1.
+func Variable(x float64) Dual {
+	return Dual{Value: x, Derivative: 1}
+}
+
+// Constant returns the dual number of the constant 'c', whose derivative is 0.
+func Constant(c float64) Dual {
+	return Dual{Value: c}
+}
+
+// Derivative returns the exact derivative of 'f' at 'x'.
+func Derivative(f func(x Dual) Dual, x float64) float64 {
+	return f(Variable(x)).Derivative
+}
+
+// Evaluate returns the value of 'f' at 'x' along with its exact derivative.
+func Evaluate(f func(x Dual) Dual, x float64) (value, derivative float64) {
+	y := f(Variable(x))
+	return y.Value, y.Derivative
+}
+
+// Split returns 'f' and its derivative as functions of a float64, which is what root finders such as equation.NewtonRaphson expect.
+func Split(f func(x Dual) Dual) (fx func(x float64) float64, dxF func(x float64) float64) {
+	fx = func(x float64) float64 {
+		return f(Constant(x)).Value
+	}
+	dxF = func(x float64) float64 {
+		return f(Variable(x)).Derivative
+	}
+	return fx, dxF
+}
+
+// Add returns a + b.
+func (a Dual) Add(b Dual) Dual {
+	return Dual{Value: a.Value + b.Value, Derivative: a.Derivative + b.Derivative}
+}
+
+// Sub returns a - b.
+func (a Dual) Sub(b Dual) Dual {
+	return Dual{Value: a.Value - b.Value, Derivative: a.Derivative - b.Derivative}
+}
+
+// Mul returns a · b.
+func (a Dual) Mul(b Dual) Dual {
+	return Dual{Value: a.Value * b.Value, Derivative: a.Derivative*b.Value + a.Value*b.Derivative}
+}
+
+// Div returns a / b.
+func (a Dual) Div(b Dual) Dual {
+	return Dual{Value: a.Value / b.Value, Derivative: (a.Derivative*b.Value - a.Value*b.Derivative) / (b.Value * b.Value)}
+}
+
+// Neg returns -a.
+func (a Dual) Neg() Dual {
+	return Dual{Value: -a.Value, Derivative: -a.Derivative}
+}
+
+// Scale returns c · a.
+func (a Dual) Scale(c float64) Dual {
+	return Dual{Value: c * a.Value, Derivative: c * a.Derivative}
+}
+
+// Shift returns a + c.
+func (a Dual) Shift(c float64) Dual {
+	return Dual{Value: a.Value + c, Derivative: a.Derivative}
+}
+
+// chain applies a function with value 'value' and derivative 'derivative' at a.Value to 'a'.
+func chain(a Dual, value, derivative float64) Dual {
+	return Dual{Value: value, Derivative: derivative * a.Derivative}
+}
+
+// Sin returns the sine of 'a'.
+func (a Dual) Sin() Dual {
+	sin, cos := math.Sincos(a.Value)
+	return chain(a, sin, cos)
+}
+
+// Cos returns the cosine of 'a'.
+func (a Dual) Cos() Dual {
+	sin, cos := math.Sincos(a.Value)
+	return chain(a, cos, -sin)
+}
+
+// Tan returns the tangent of 'a'.
+func (a Dual) Tan() Dual {
+	cos := math.Cos(a.Value)
+	return chain(a, math.Tan(a.Value), 1/(cos*cos))
+}
+
+// Asin returns the arcsine of 'a'.
+func (a Dual) Asin() Dual {
+	return chain(a, math.Asin(a.Value), 1/math.Sqrt(1-a.Value*a.Value))
+}
+
+// Acos returns the arccosine of 'a'.
+func (a Dual) Acos() Dual {
+	return chain(a, math.Acos(a.Value), -1/math.Sqrt(1-a.Value*a.Value))
+}
+
+// Atan returns the arctangent of 'a'.
+func (a Dual) Atan() Dual {
+	return chain(a, math.Atan(a.Value), 1/(1+a.Value*a.Value))
+}
+
+// Sinh returns the hyperbolic sine of 'a'.
+func (a Dual) Sinh() Dual {
+	return chain(a, math.Sinh(a.Value), math.Cosh(a.Value))
+}
+
+// Cosh returns the hyperbolic cosine of 'a'.
+func (a Dual) Cosh() Dual {
+	return chain(a, math.Cosh(a.Value), math.Sinh(a.Value))
+}
+
+// Tanh returns the hyperbolic tangent of 'a'.
+func (a Dual) Tanh() Dual {
+	tanh := math.Tanh(a.Value)
+	return chain(a, tanh, 1-tanh*tanh)
+}
+
+// Exp returns e raised to 'a'.
+func (a Dual) Exp() Dual {
+	exp := math.Exp(a.Value)
+	return chain(a, exp, exp)
+}
+
+// Log returns the natural logarithm of 'a'.
+func (a Dual) Log() Dual {
+	return chain(a, math.Log(a.Value), 1/a.Value)
+}
+
+// Sqrt returns the square root of 'a'.
+func (a Dual) Sqrt() Dual {
+	sqrt := math.Sqrt(a.Value)
+	return chain(a, sqrt, 1/(2*sqrt))
+}
+
+// Abs returns the absolute value of 'a'. Its derivative at 0 is taken as 0.
+func (a Dual) Abs() Dual {
+	switch {
+	case a.Value > 0:
+		return a
+	case a.Value < 0:
+		return a.Neg()
+	}
+	return Dual{}
+}
+
+// Pow returns 'a' raised to the constant power 'p'.
+func (a Dual) Pow(p float64) Dual {
+	if p == 0 {
+		return Constant(1)
+	}
+	return chain(a, math.Pow(a.Value, p), p*math.Pow(a.Value, p-1))
+}
+
+// PowDual returns 'a' raised to the power 'b', where both may depend on the variable. The value of 'a' must be positive.
+func (a Dual) PowDual(b Dual) Dual {
+	pow := math.Pow(a.Value, b.Value)
+	return Dual{Value: pow, Derivative: pow * (b.Derivative*math.Log(a.Value) + b.Value*a.Derivative/a.Value)}
+}
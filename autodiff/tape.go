@@ -0,0 +1,298 @@
+package autodiff
+
+import "math"
+
+// Tape records the operations made on its [Var] values, so that the gradient of a result with respect to every input
+// can be computed in a single backward pass, whatever the number of inputs. This is reverse-mode automatic differentiation,
+// which is cheaper than [Dual] numbers when a function has many inputs and a single output.
+//
+// A tape can be reused across evaluations: [Tape.Reset], which [Tape.Gradient] calls, forgets the recorded operations
+// but keeps the allocated memory. A tape must not be used by several goroutines at once.
+type Tape struct {
+	nodes    []node
+	inputs   []int
+	adjoints []float64
+	// generation counts the resets, so that values recorded before the last one are detected
+	generation uint
+}
+
+// node is an operation recorded on the tape, with the indices of up to two operands and the partial derivatives with respect to them.
+type node struct {
+	operands [2]int
+	partials [2]float64
+	arity    int
+}
+
+// Var is a value recorded on a [Tape]. It is created with [Tape.Variable] or [Tape.Constant], or as the result of an operation on other values
+// of the same tape. Combining values of different tapes, or using a value that was not recorded on a tape or was recorded before
+// the last [Tape.Reset], raises a panic.
+type Var struct {
+	tape       *Tape
+	index      int
+	value      float64
+	generation uint
+}
+
+// NewTape creates and returns a pointer to a new, empty [Tape].
+func NewTape() *Tape {
+	return &Tape{}
+}
+
+// Variable records a new input with value 'x'. The gradient returned by [Tape.Backward] holds one entry per input, in the order they were created.
+func (t *Tape) Variable(x float64) Var {
+	v := t.push(x, node{})
+	t.inputs = append(t.inputs, v.index)
+	return v
+}
+
+// Constant records the constant 'c', which is not an input.
+func (t *Tape) Constant(c float64) Var {
+	return t.push(c, node{})
+}
+
+// Reset forgets every recorded operation and input while keeping the allocated memory, so the tape can record a new evaluation.
+// Values recorded before the reset must not be used afterwards, and doing so raises a panic.
+func (t *Tape) Reset() {
+	t.nodes = t.nodes[:0]
+	t.inputs = t.inputs[:0]
+	t.generation++
+}
+
+// Len returns the number of operations recorded on the tape, including inputs and constants.
+func (t *Tape) Len() int {
+	return len(t.nodes)
+}
+
+// Backward propagates the derivative of 'output' back through the tape, and returns its partial derivatives with respect to every input,
+// in the order they were created.
+func (t *Tape) Backward(output Var) []float64 {
+	t.check(output)
+	if cap(t.adjoints) < len(t.nodes) {
+		t.adjoints = make([]float64, len(t.nodes))
+	}
+	t.adjoints = t.adjoints[:len(t.nodes)]
+	for i := range t.adjoints {
+		t.adjoints[i] = 0
+	}
+
+	t.adjoints[output.index] = 1
+	for i := output.index; i >= 0; i-- {
+		adjoint := t.adjoints[i]
+		if adjoint == 0 {
+			continue
+		}
+		n := t.nodes[i]
+		for k := 0; k < n.arity; k++ {
+			t.adjoints[n.operands[k]] += n.partials[k] * adjoint
+		}
+	}
+
+	gradient := make([]float64, len(t.inputs))
+	for i, index := range t.inputs {
+		gradient[i] = t.adjoints[index]
+	}
+	return gradient
+}
+
+// Gradient resets the tape, records 'f' evaluated at 'x' and returns its value together with its gradient,
+// that is, the partial derivative of 'f' with respect to every element of 'x'.
+func (t *Tape) Gradient(f func(x []Var) Var, x []float64) (value float64, gradient []float64) {
+	output := t.record(f, x)
+	return output.value, t.Backward(output)
+}
+
+// Gradient returns the value of 'f' at 'x' together with its gradient, recorded on a new [Tape].
+// Use [Tape.Gradient] to reuse a tape across evaluations.
+func Gradient(f func(x []Var) Var, x []float64) (value float64, gradient []float64) {
+	return NewTape().Gradient(f, x)
+}
+
+// SplitGradient returns 'f' and its gradient as functions of a []float64, which is what multivariate optimizers and root finders expect.
+// The value function only records the evaluation, without the backward pass.
+// Both functions share a single tape, so they must not be called by several goroutines at once.
+func SplitGradient(f func(x []Var) Var) (fx func(x []float64) float64, gradient func(x []float64) []float64) {
+	t := NewTape()
+	fx = func(x []float64) float64 {
+		return t.record(f, x).value
+	}
+	gradient = func(x []float64) []float64 {
+		_, g := t.Gradient(f, x)
+		return g
+	}
+	return fx, gradient
+}
+
+// record resets the tape and records 'f' evaluated at 'x', returning its output.
+func (t *Tape) record(f func(x []Var) Var, x []float64) Var {
+	t.Reset()
+	vars := make([]Var, len(x))
+	for i := range x {
+		vars[i] = t.Variable(x[i])
+	}
+	return f(vars)
+}
+
+func (t *Tape) push(value float64, n node) Var {
+	t.nodes = append(t.nodes, n)
+	return Var{tape: t, index: len(t.nodes) - 1, value: value, generation: t.generation}
+}
+
+func (t *Tape) check(v Var) {
+	if v.recorded() != t {
+		panic("autodiff: the value was recorded on a different tape")
+	}
+}
+
+// recorded returns the tape of 'a', after checking that 'a' is still recorded on it.
+func (a Var) recorded() *Tape {
+	if a.tape == nil {
+		panic("autodiff: the value was not recorded on a tape, create it with Tape.Variable or Tape.Constant")
+	}
+	if a.generation != a.tape.generation {
+		panic("autodiff: the value was recorded before the last Reset of its tape")
+	}
+	return a.tape
+}
+
+// unary records an operation on 'a' with the given value and partial derivative.
+func (a Var) unary(value, partial float64) Var {
+	return a.recorded().push(value, node{operands: [2]int{a.index}, partials: [2]float64{partial}, arity: 1})
+}
+
+// binary records an operation on 'a' and 'b' with the given value and partial derivatives.
+func (a Var) binary(b Var, value, partialA, partialB float64) Var {
+	t := a.recorded()
+	t.check(b)
+	return t.push(value, node{operands: [2]int{a.index, b.index}, partials: [2]float64{partialA, partialB}, arity: 2})
+}
+
+// Value returns the value of 'a'.
+func (a Var) Value() float64 {
+	return a.value
+}
+
+// Add returns a + b.
+func (a Var) Add(b Var) Var {
+	return a.binary(b, a.value+b.value, 1, 1)
+}
+
+// Sub returns a - b.
+func (a Var) Sub(b Var) Var {
+	return a.binary(b, a.value-b.value, 1, -1)
+}
+
+// Mul returns a · b.
+func (a Var) Mul(b Var) Var {
+	return a.binary(b, a.value*b.value, b.value, a.value)
+}
+
+// Div returns a / b.
+func (a Var) Div(b Var) Var {
+	return a.binary(b, a.value/b.value, 1/b.value, -a.value/(b.value*b.value))
+}
+
+// Neg returns -a.
+func (a Var) Neg() Var {
+	return a.unary(-a.value, -1)
+}
+
+// Scale returns c · a.
+func (a Var) Scale(c float64) Var {
+	return a.unary(c*a.value, c)
+}
+
+// Shift returns a + c.
+func (a Var) Shift(c float64) Var {
+	return a.unary(a.value+c, 1)
+}
+
+// Sin returns the sine of 'a'.
+func (a Var) Sin() Var {
+	sin, cos := math.Sincos(a.value)
+	return a.unary(sin, cos)
+}
+
+// Cos returns the cosine of 'a'.
+func (a Var) Cos() Var {
+	sin, cos := math.Sincos(a.value)
+	return a.unary(cos, -sin)
+}
+
+// Tan returns the tangent of 'a'.
+func (a Var) Tan() Var {
+	cos := math.Cos(a.value)
+	return a.unary(math.Tan(a.value), 1/(cos*cos))
+}
+
+// Asin returns the arcsine of 'a'.
+func (a Var) Asin() Var {
+	return a.unary(math.Asin(a.value), 1/math.Sqrt(1-a.value*a.value))
+}
+
+// Acos returns the arccosine of 'a'.
+func (a Var) Acos() Var {
+	return a.unary(math.Acos(a.value), -1/math.Sqrt(1-a.value*a.value))
+}
+
+// Atan returns the arctangent of 'a'.
+func (a Var) Atan() Var {
+	return a.unary(math.Atan(a.value), 1/(1+a.value*a.value))
+}
+
+// Sinh returns the hyperbolic sine of 'a'.
+func (a Var) Sinh() Var {
+	return a.unary(math.Sinh(a.value), math.Cosh(a.value))
+}
+
+// Cosh returns the hyperbolic cosine of 'a'.
+func (a Var) Cosh() Var {
+	return a.unary(math.Cosh(a.value), math.Sinh(a.value))
+}
+
+// Tanh returns the hyperbolic tangent of 'a'.
+func (a Var) Tanh() Var {
+	tanh := math.Tanh(a.value)
+	return a.unary(tanh, 1-tanh*tanh)
+}
+
+// Exp returns e raised to 'a'.
+func (a Var) Exp() Var {
+	exp := math.Exp(a.value)
+	return a.unary(exp, exp)
+}
+
+// Log returns the natural logarithm of 'a'.
+func (a Var) Log() Var {
+	return a.unary(math.Log(a.value), 1/a.value)
+}
+
+// Sqrt returns the square root of 'a'.
+func (a Var) Sqrt() Var {
+	sqrt := math.Sqrt(a.value)
+	return a.unary(sqrt, 1/(2*sqrt))
+}
+
+// Abs returns the absolute value of 'a'. Its derivative at 0 is taken as 0.
+func (a Var) Abs() Var {
+	switch {
+	case a.value > 0:
+		return a.unary(a.value, 1)
+	case a.value < 0:
+		return a.unary(-a.value, -1)
+	}
+	return a.unary(0, 0)
+}
+
+// Pow returns 'a' raised to the constant power 'p'.
+func (a Var) Pow(p float64) Var {
+	if p == 0 {
+		return a.unary(1, 0)
+	}
+	return a.unary(math.Pow(a.value, p), p*math.Pow(a.value, p-1))
+}
+
+// PowVar returns 'a' raised to the power 'b', where both are recorded values. The value of 'a' must be positive.
+func (a Var) PowVar(b Var) Var {
+	pow := math.Pow(a.value, b.value)
+	return a.binary(b, pow, b.value*pow/a.value, pow*math.Log(a.value))
+}
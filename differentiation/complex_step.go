@@ -0,0 +1,56 @@
+package differentiation
+
+import "github.com/rocas777/kairos"
+
+// ComplexStep provides methods for calculating the first derivative of a function that accepts complex arguments, using the [complex-step] method.
+// The derivative is computed as Im(f(x + i·H))/H. Unlike the [Simple] and [Symmetric] differences, no subtraction is involved,
+// so there is no cancellation and 'H' can be made tiny: the result is then accurate to machine precision.
+// 'f' must be analytic near 'x' and be written with operations that extend it to complex numbers, such as those of the math/cmplx package.
+// Functions like abs, min or max, and comparisons on the real part, break this requirement.
+//
+// ComplexStep does not implement the [Differentiator] interface, as its functions take and return complex128 values.
+//
+// If 'H' is not specified, it defaults to 1e-20. If 'H' is less than 0, a panic is raised.
+//
+// [complex-step]: https://en.wikipedia.org/wiki/Numerical_differentiation#Complex-variable_methods
+type ComplexStep struct {
+	H float64
+}
+
+// NewComplexStep creates and returns a pointer to a new [ComplexStep] instance with the specified value of 'h'.
+//
+// If 'h' is less than 0, a panic is raised.
+func NewComplexStep(h float64) *ComplexStep {
+	return &ComplexStep{H: h}
+}
+
+// LocalDerivative calculates the first order derivative of the function 'f' at the point 'x' using the ComplexStep method.
+// It returns the calculated derivative value.
+func (s *ComplexStep) LocalDerivative(f func(z complex128) complex128, x float64) float64 {
+	s.handleInput()
+	return imag(f(complex(x, s.H))) / s.H
+}
+
+func (s *ComplexStep) handleInput() {
+	if s.H == 0 {
+		s.H = 1e-20
+	} else if s.H <= 0 {
+		panic("ComplexStep struct value of H should be higher than 0")
+	}
+}
+
+// RangeDerivative calculates the first order derivative of the function 'f' over the specified range [a, b] using the [ComplexStep] method.
+// It divides the range into 'samples' points and returns a slice of [kairos.Pair] representing the points and their corresponding derivative values.
+func (s *ComplexStep) RangeDerivative(f func(z complex128) complex128, a, b float64, samples uint) []kairos.Pair {
+	s.handleInput()
+	if samples < 2 {
+		samples = 2
+	}
+	out := make([]kairos.Pair, samples)
+	sampleH := (b - a) / float64(samples-1)
+	for i := 0; i < int(samples); i++ {
+		x := a + float64(i)*sampleH
+		out[i] = kairos.Pair{X: x, Y: s.LocalDerivative(f, x)}
+	}
+	return out
+}
@@ -1,8 +1,11 @@
 package differentiation_test
 
 import (
+	"github.com/rocas777/kairos"
 	"github.com/rocas777/kairos/differentiation"
 	"math"
+	"math/cmplx"
+	"math/rand"
 	"testing"
 )
 
@@ -134,3 +137,481 @@ func TestHigherOrder(t *testing.T) {
 		})
 	}
 }
+
+func TestRidders(t *testing.T) {
+	x := 3.0
+
+	tests := []struct {
+		name string
+		f    func(x float64) float64
+		dxF  func(x float64) float64
+		h    float64
+	}{
+		{"smooth", smooth, dxSmooth, 0},
+		{"oscillatory", oscillatory, dxOscillatory, 0},
+		{"exponential", exponential, dxExponential, 0},
+		{"singularity", singularity, dxSingularity, 0},
+		{"polynomial", polynomial, dxPolynomial, 0},
+		{"large_step", oscillatory, dxOscillatory, 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ridders := differentiation.NewRidders(test.h)
+			r := ridders.LocalDerivativeResult(test.f, x)
+			want := test.dxF(x)
+			if math.Abs(r.Derivative-want) > 1e-9 {
+				t.Fatalf("Got: %.12f ± %g, wanted: %.12f", r.Derivative, r.ErrorEstimate, want)
+			}
+			if r.ErrorEstimate > 1e-8 || r.Step <= 0 || r.Step > ridders.H || r.Evaluations < 4 {
+				t.Fatalf("Got: %+v", r)
+			}
+			check(ridders.LocalDerivative(test.f, x), want, t)
+		})
+	}
+}
+
+func TestComplexStep(t *testing.T) {
+	x := 3.0
+
+	tests := []struct {
+		name string
+		f    func(z complex128) complex128
+		dxF  func(x float64) float64
+	}{
+		{"smooth", func(z complex128) complex128 { return cmplx.Exp(-z*z) - 0.3 }, dxSmooth},
+		{"oscillatory", cmplx.Sin, dxOscillatory},
+		{"exponential", func(z complex128) complex128 { return cmplx.Exp(z) - 4 }, dxExponential},
+		{"singularity", func(z complex128) complex128 { return 1/z - 0.2 }, dxSingularity},
+		{"polynomial", func(z complex128) complex128 { return z * z * z }, dxPolynomial},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			complexStep := differentiation.NewComplexStep(0)
+			got := complexStep.LocalDerivative(test.f, x)
+			if want := test.dxF(x); math.Abs(got-want) > 1e-14*math.Max(1, math.Abs(want)) {
+				t.Fatalf("Got: %.17f, wanted: %.17f", got, want)
+			}
+			for _, p := range complexStep.RangeDerivative(test.f, 1, 3, 5) {
+				check(p.Y, test.dxF(p.X), t)
+			}
+		})
+	}
+}
+
+func TestStencil(t *testing.T) {
+	tests := []struct {
+		name    string
+		order   uint
+		offsets []float64
+		want    []float64
+	}{
+		{"central_first", 1, differentiation.CentralOffsets(1, 2), []float64{-0.5, 0, 0.5}},
+		{"central_second", 2, differentiation.CentralOffsets(2, 2), []float64{1, -2, 1}},
+		{"central_first_fourth_accuracy", 1, differentiation.CentralOffsets(1, 4), []float64{1.0 / 12, -2.0 / 3, 0, 2.0 / 3, -1.0 / 12}},
+		{"central_third", 3, differentiation.CentralOffsets(3, 2), []float64{-0.5, 1, 0, -1, 0.5}},
+		{"forward_first", 1, differentiation.ForwardOffsets(1, 2), []float64{-1.5, 2, -0.5}},
+		{"backward_first", 1, differentiation.BackwardOffsets(1, 2), []float64{1.5, -2, 0.5}},
+		{"forward_second", 2, differentiation.ForwardOffsets(2, 1), []float64{1, -2, 1}},
+		{"non_uniform", 1, []float64{-1, 0, 2}, []float64{-2.0 / 3, 0.5, 1.0 / 6}},
+		{"unordered", 2, []float64{1, -1, 0}, []float64{1, 1, -2}},
+		{"interpolation", 0, []float64{-1, 1}, []float64{0.5, 0.5}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := differentiation.Stencil(test.order, test.offsets)
+			if len(got) != len(test.want) {
+				t.Fatalf("Got: %v, wanted: %v", got, test.want)
+			}
+			for i := range got {
+				if math.Abs(got[i]-test.want[i]) > 1e-14 {
+					t.Fatalf("Got: %v, wanted: %v", got, test.want)
+				}
+			}
+		})
+	}
+	if got := len(differentiation.CentralOffsets(4, 3)); got != 7 {
+		t.Fatalf("Got %d offsets for an odd accuracy, wanted 7", got)
+	}
+}
+
+func TestFiniteDifference(t *testing.T) {
+	h := 0.01
+	x := 3.0
+
+	tests := []struct {
+		name    string
+		f       func(x float64) float64
+		dxF     func(x float64) float64
+		order   uint
+		offsets []float64
+	}{
+		{"smooth", smooth, dxSmooth, 1, nil},
+		{"oscillatory", oscillatory, dxOscillatory, 1, differentiation.CentralOffsets(1, 8)},
+		{"exponential_forward", exponential, dxExponential, 2, differentiation.ForwardOffsets(2, 4)},
+		{"exponential_backward", exponential, dxExponential, 3, differentiation.BackwardOffsets(3, 4)},
+		{"singularity", singularity, dxSingularity, 1, []float64{-1, 0.5, 2, 3}},
+		{"polynomial1", polynomial, dxPolynomial, 1, differentiation.ForwardOffsets(1, 2)},
+		{"polynomial3", polynomial, dx3Polynomial, 3, []float64{-2, -0.5, 1, 1.5}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := differentiation.NewFiniteDifference(h, test.order, test.offsets)
+			check(m.LocalDerivative(test.f, x), test.dxF(x), t)
+			for _, p := range m.RangeDerivative(test.f, 2, 3, 3) {
+				check(p.Y, test.dxF(p.X), t)
+			}
+		})
+	}
+
+	t.Run("evaluations", func(t *testing.T) {
+		for order := uint(1); order <= 6; order++ {
+			evaluations := 0
+			counted := func(x float64) float64 {
+				evaluations++
+				return exponential(x)
+			}
+			m := differentiation.HigherOrder{H: 0.01, Order: order}
+			check(m.LocalDerivative(counted, x), dxExponential(x), t)
+			if evaluations != int(order)+1 {
+				t.Fatalf("Got %d evaluations for order %d, wanted %d", evaluations, order, order+1)
+			}
+		}
+	})
+}
+
+func TestMultivariate(t *testing.T) {
+	// f(x, y, z) = x²y + e^x·sin(z)
+	f := func(x []float64) float64 {
+		return x[0]*x[0]*x[1] + math.Exp(x[0])*math.Sin(x[2])
+	}
+	gradient := func(x []float64) []float64 {
+		return []float64{2*x[0]*x[1] + math.Exp(x[0])*math.Sin(x[2]), x[0] * x[0], math.Exp(x[0]) * math.Cos(x[2])}
+	}
+	hessian := func(x []float64) [][]float64 {
+		e, sin, cos := math.Exp(x[0]), math.Sin(x[2]), math.Cos(x[2])
+		return [][]float64{
+			{2*x[1] + e*sin, 2 * x[0], e * cos},
+			{2 * x[0], 0, 0},
+			{e * cos, 0, -e * sin},
+		}
+	}
+	// g(x, y) = (x·y, sin(x) + y², y³)
+	buffer := make([]float64, 3)
+	g := func(x []float64) []float64 {
+		buffer[0], buffer[1], buffer[2] = x[0]*x[1], math.Sin(x[0])+x[1]*x[1], x[1]*x[1]*x[1]
+		return buffer
+	}
+	jacobian := func(x []float64) [][]float64 {
+		return [][]float64{{x[1], x[0]}, {math.Cos(x[0]), 2 * x[1]}, {0, 3 * x[1] * x[1]}}
+	}
+	x := []float64{0.7, -1.3, 2.1}
+	y := []float64{1.5, 30}
+
+	matrix := func(got, want [][]float64, tolerance float64, t *testing.T) {
+		if len(got) != len(want) {
+			t.Fatalf("Got: %v, wanted: %v", got, want)
+		}
+		for i := range want {
+			for j := range want[i] {
+				if math.Abs(got[i][j]-want[i][j]) > tolerance*math.Max(1, math.Abs(want[i][j])) {
+					t.Fatalf("Got: %v, wanted: %v", got, want)
+				}
+			}
+		}
+	}
+
+	tests := []struct {
+		name      string
+		scheme    differentiation.Scheme
+		tolerance float64
+	}{
+		{"central", differentiation.CentralScheme, 1e-8},
+		{"forward", differentiation.ForwardScheme, 1e-5},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := differentiation.NewMultivariate(0, test.scheme)
+			n := uint(len(x))
+
+			matrix([][]float64{m.Gradient(f, x)}, [][]float64{gradient(x)}, test.tolerance, t)
+			want := 2 * n
+			if test.scheme == differentiation.ForwardScheme {
+				want = n + 1
+			}
+			if m.Cycles() != want {
+				t.Fatalf("Got %d evaluations, wanted %d", m.Cycles(), want)
+			}
+
+			matrix(m.Jacobian(g, y), jacobian(y), test.tolerance, t)
+
+			matrix(m.Hessian(f, x), hessian(x), 1e3*test.tolerance, t)
+			want = 2*n*n + 1
+			if test.scheme == differentiation.ForwardScheme {
+				want = (n + 1) * (n + 2) / 2
+			}
+			if m.Cycles() != want {
+				t.Fatalf("Got %d evaluations, wanted %d", m.Cycles(), want)
+			}
+
+			v := []float64{1, -2, 0.5}
+			dot := 0.0
+			for i, d := range gradient(x) {
+				dot += d * v[i]
+			}
+			matrix([][]float64{{m.DirectionalDerivative(f, x, v)}}, [][]float64{{dot}}, test.tolerance, t)
+			if m.DirectionalDerivative(f, x, []float64{0, 0, 0}) != 0 {
+				t.Fatalf("Got a non-zero derivative along a zero direction")
+			}
+		})
+	}
+}
+
+func TestSampledDerivative(t *testing.T) {
+	// unevenly spaced samples of sin(x) over [0, 3], denser near 0
+	samples := func(n int, noise float64) []kairos.Pair {
+		r := rand.New(rand.NewSource(1))
+		data := make([]kairos.Pair, n)
+		for i := range data {
+			x := 3 * math.Pow(float64(i)/float64(n-1), 1.2)
+			data[i] = kairos.Pair{X: x, Y: math.Sin(x) + noise*(2*r.Float64()-1)}
+		}
+		return data
+	}
+	// rms returns the root mean square error of 'got' against the derivative 'dxF'
+	rms := func(got []kairos.Pair, dxF func(x float64) float64) float64 {
+		sum := 0.0
+		for _, p := range got {
+			sum += (p.Y - dxF(p.X)) * (p.Y - dxF(p.X))
+		}
+		return math.Sqrt(sum / float64(len(got)))
+	}
+	d2Sin := func(x float64) float64 { return -math.Sin(x) }
+	clean, noisy := samples(101, 0), samples(301, 1e-3)
+
+	tests := []struct {
+		name      string
+		got       []kairos.Pair
+		dxF       func(x float64) float64
+		tolerance float64
+	}{
+		{"finite_difference", differentiation.SampledDerivative(clean, 1), math.Cos, 1e-3},
+		{"finite_difference_second", differentiation.SampledDerivative(clean, 2), d2Sin, 1e-2},
+		{"savitzky_golay_clean", differentiation.NewSavitzkyGolay(7, 4).SampledDerivative(clean), math.Cos, 1e-5},
+		{"savitzky_golay_noisy", differentiation.NewSavitzkyGolay(41, 3).SampledDerivative(noisy), math.Cos, 1e-2},
+		{"savitzky_golay_second", (&differentiation.SavitzkyGolay{Window: 61, Degree: 4, Order: 2}).SampledDerivative(noisy), d2Sin, 0.1},
+		{"savitzky_golay_smooth", differentiation.NewSavitzkyGolay(41, 3).Smooth(noisy), math.Sin, 5e-4},
+		{"total_variation_clean", differentiation.NewTotalVariation(1e-8).SampledDerivative(clean), math.Cos, 1e-2},
+		{"total_variation_noisy", differentiation.NewTotalVariation(1e-4).SampledDerivative(noisy), math.Cos, 2e-2},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if len(test.got) != len(clean) && len(test.got) != len(noisy) {
+				t.Fatalf("Got %d samples", len(test.got))
+			}
+			if err := rms(test.got, test.dxF); err > test.tolerance {
+				t.Fatalf("Got a root mean square error of %g, wanted less than %g", err, test.tolerance)
+			}
+		})
+	}
+
+	t.Run("noise_amplification", func(t *testing.T) {
+		fd := rms(differentiation.SampledDerivative(noisy, 1), math.Cos)
+		sg := rms(differentiation.NewSavitzkyGolay(41, 3).SampledDerivative(noisy), math.Cos)
+		tv := rms(differentiation.NewTotalVariation(1e-4).SampledDerivative(noisy), math.Cos)
+		if sg > fd/5 || tv > fd/3 {
+			t.Fatalf("Got root mean square errors of %g (finite differences), %g (Savitzky-Golay) and %g (total variation)", fd, sg, tv)
+		}
+	})
+
+	t.Run("polynomial", func(t *testing.T) {
+		// polynomials up to the degree of the filter are reproduced exactly, including at the ends
+		data := []kairos.Pair{{X: -1}, {X: -0.7}, {X: 0}, {X: 0.2}, {X: 1.1}, {X: 1.5}, {X: 2.4}, {X: 3}}
+		for i := range data {
+			data[i].Y = polynomial(data[i].X)
+		}
+		for _, p := range differentiation.NewSavitzkyGolay(5, 3).SampledDerivative(data) {
+			if math.Abs(p.Y-dxPolynomial(p.X)) > 1e-10 {
+				t.Fatalf("Got: %v, wanted: %f", p, dxPolynomial(p.X))
+			}
+		}
+		for _, p := range differentiation.SampledDerivative(data, 3) {
+			if math.Abs(p.Y-dx3Polynomial(p.X)) > 1e-9 {
+				t.Fatalf("Got: %v, wanted: %f", p, dx3Polynomial(p.X))
+			}
+		}
+	})
+
+	t.Run("jump", func(t *testing.T) {
+		// the derivative of |x - 1| jumps from -1 to 1, which total variation keeps sharp
+		r := rand.New(rand.NewSource(2))
+		data := make([]kairos.Pair, 201)
+		for i := range data {
+			x := 2 * float64(i) / 200
+			data[i] = kairos.Pair{X: x, Y: math.Abs(x-1) + 1e-3*(2*r.Float64()-1)}
+		}
+		for _, p := range differentiation.NewTotalVariation(1e-4).SampledDerivative(data) {
+			if math.Abs(p.X-1) > 0.1 && math.Abs(math.Abs(p.Y)-1) > 0.05 {
+				t.Fatalf("Got: %v, wanted: ±1", p)
+			}
+		}
+	})
+}
+
+func TestDomain(t *testing.T) {
+	domain := differentiation.NewDomain(1, 3)
+	// inside returns 'f' restricted to the domain, failing the test if it is evaluated outside
+	inside := func(f func(x float64) float64, t *testing.T) func(x float64) float64 {
+		return func(x float64) float64 {
+			if !domain.Contains(x) {
+				t.Fatalf("Evaluated at %f, outside of [%f, %f]", x, domain.Lo, domain.Hi)
+			}
+			return f(x)
+		}
+	}
+
+	tests := []struct {
+		name string
+		m    differentiation.Differentiator
+		f    func(x float64) float64
+		dxF  func(x float64) float64
+	}{
+		{"symmetric", &differentiation.Symmetric{H: 0.01, Domain: domain}, math.Sqrt, func(x float64) float64 { return 0.5 / math.Sqrt(x) }},
+		{"symmetric_oscillatory", &differentiation.Symmetric{H: 0.001, Domain: domain}, oscillatory, dxOscillatory},
+		{"higher_order1", &differentiation.HigherOrder{H: 0.01, Order: 1, Domain: domain}, exponential, dxExponential},
+		{"higher_order2", &differentiation.HigherOrder{H: 0.01, Order: 2, Domain: domain}, exponential, dxExponential},
+		{"higher_order3", &differentiation.HigherOrder{H: 0.01, Order: 3, Domain: domain}, exponential, dxExponential},
+		{"finite_difference", &differentiation.FiniteDifference{H: 0.05, Offsets: differentiation.CentralOffsets(1, 4), Domain: domain}, singularity, dxSingularity},
+		{"finite_difference2", &differentiation.FiniteDifference{H: 0.01, Order: 2, Domain: domain}, polynomial, dx2Polynomial},
+		// the step is shrunk to fit the domain, and the one-sided difference is exact for parabolas
+		{"narrow", &differentiation.Symmetric{H: 10, Domain: domain}, func(x float64) float64 { return x * x }, func(x float64) float64 { return 2 * x }},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f := inside(test.f, t)
+			for _, x := range []float64{1, 1.005, 2, 2.995, 3} {
+				check(test.m.LocalDerivative(f, x), test.dxF(x), t)
+			}
+			for _, p := range test.m.RangeDerivative(f, 1, 3, 21) {
+				check(p.Y, test.dxF(p.X), t)
+			}
+		})
+	}
+
+	t.Run("accuracy", func(t *testing.T) {
+		// the one-sided stencils keep the accuracy of the central one, so halving the step divides the error by 16
+		errorAt := func(h, x float64) float64 {
+			m := differentiation.FiniteDifference{H: h, Offsets: differentiation.CentralOffsets(1, 4), Domain: domain}
+			return math.Abs(m.LocalDerivative(exponential, x) - dxExponential(x))
+		}
+		for _, x := range []float64{1, 3} {
+			if ratio := errorAt(0.04, x) / errorAt(0.02, x); ratio < 12 {
+				t.Fatalf("Got an error ratio of %f at %f, wanted about 16", ratio, x)
+			}
+		}
+	})
+
+	t.Run("outside", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Fatalf("Expected a panic for a point outside of the domain")
+			}
+		}()
+		(&differentiation.Symmetric{Domain: domain}).LocalDerivative(math.Sqrt, 0.5)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Fatalf("Expected a panic for a domain whose Lo is not lower than Hi")
+			}
+		}()
+		differentiation.NewDomain(3, 1)
+	})
+}
+
+func TestTaylor(t *testing.T) {
+	factorial := func(n int) float64 {
+		out := 1.0
+		for i := 2; i <= n; i++ {
+			out *= float64(i)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name string
+		f    func(z complex128) complex128
+		x    float64
+		// coefficient returns the exact coefficient of order n
+		coefficient func(n int) float64
+	}{
+		{"exponential", cmplx.Exp, 1, func(n int) float64 { return math.E / factorial(n) }},
+		{"oscillatory", cmplx.Sin, 0, func(n int) float64 {
+			if n%2 == 0 {
+				return 0
+			}
+			return math.Pow(-1, float64(n/2)) / factorial(n)
+		}},
+		{"singularity", func(z complex128) complex128 { return 1 / z }, 3, func(n int) float64 { return math.Pow(-1, float64(n)) / math.Pow(3, float64(n+1)) }},
+		{"polynomial", func(z complex128) complex128 { return z * z * z }, 3, func(n int) float64 { return []float64{27, 27, 9, 1, 0, 0, 0, 0, 0, 0, 0, 0}[n] }},
+		{"far", cmplx.Exp, 100, func(n int) float64 { return math.Exp(100) / factorial(n) }},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			taylor := differentiation.NewTaylor(0, 0)
+			s := taylor.Series(test.f, test.x, 12)
+			if len(s.Coefficients) != 12 || len(s.ErrorEstimates) != 12 || s.Evaluations == 0 {
+				t.Fatalf("Got: %+v", s)
+			}
+			scale := 0.0
+			for n := range s.Coefficients {
+				scale = math.Max(scale, math.Abs(test.coefficient(n)))
+			}
+			for n, got := range s.Coefficients {
+				want := test.coefficient(n)
+				err := math.Abs(got - want)
+				// relative to the coefficient, or to the function for the coefficients that vanish
+				if err > 1e-9*math.Max(math.Abs(want), 1e-3*scale) {
+					t.Fatalf("Got coefficient %d: %g ± %g, wanted: %g (radius %g)", n, got, s.ErrorEstimates[n], want, s.Radius)
+				}
+				if err > 10*s.ErrorEstimates[n] {
+					t.Fatalf("Got coefficient %d: %g ± %g, but the error is %g", n, got, s.ErrorEstimates[n], err)
+				}
+			}
+		})
+	}
+
+	t.Run("fixed_radius", func(t *testing.T) {
+		taylor := differentiation.NewTaylor(0.5, 64)
+		s := taylor.Series(cmplx.Exp, 1, 10)
+		if s.Radius != 0.5 || s.Evaluations != 64 {
+			t.Fatalf("Got: %+v", s)
+		}
+		for n := uint(0); n < 10; n++ {
+			check(s.Derivative(n), math.E, t)
+		}
+		check(s.Evaluate(1.3), math.Exp(1.3), t)
+		if math.Abs(s.Evaluate(1.3)-math.Exp(1.3)) > 1e-10 {
+			t.Fatalf("Got: %.15f, wanted: %.15f", s.Evaluate(1.3), math.Exp(1.3))
+		}
+	})
+
+	t.Run("pole", func(t *testing.T) {
+		// the automatic radius stays away from the pole of 1/z at 0
+		s := differentiation.NewTaylor(0, 0).Series(func(z complex128) complex128 { return 1 / z }, 3, 8)
+		if s.Radius >= 3 {
+			t.Fatalf("Got a circle of radius %g around 3, which encloses the pole", s.Radius)
+		}
+	})
+
+	t.Run("overflow", func(t *testing.T) {
+		// e^(z³) overflows on the largest circles, whose coefficients are NaN; e^(z³) = 1 + z³ + ...
+		s := differentiation.NewTaylor(0, 0).Series(func(z complex128) complex128 { return cmplx.Exp(z * z * z) }, 0, 6)
+		want := []float64{1, 0, 0, 1, 0, 0}
+		for n, c := range s.Coefficients {
+			if !(math.Abs(c-want[n]) <= 1e-10) {
+				t.Fatalf("Got coefficients %v on a circle of radius %g, wanted %v", s.Coefficients, s.Radius, want)
+			}
+		}
+	})
+}
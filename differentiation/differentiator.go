@@ -0,0 +1,18 @@
+package differentiation
+
+import "github.com/rocas777/kairos"
+
+// Differentiator is implemented by every method of this package that differentiates a function of a single variable.
+// It allows callers to choose, or fall back between, differentiation methods without depending on their concrete types.
+type Differentiator interface {
+	LocalDerivative(f func(x float64) float64, x float64) float64
+	RangeDerivative(f func(x float64) float64, a, b float64, samples uint) []kairos.Pair
+}
+
+var (
+	_ Differentiator = (*Simple)(nil)
+	_ Differentiator = (*Symmetric)(nil)
+	_ Differentiator = (*HigherOrder)(nil)
+	_ Differentiator = (*Ridders)(nil)
+	_ Differentiator = (*FiniteDifference)(nil)
+)
@@ -0,0 +1,143 @@
+package differentiation
+
+import (
+	"github.com/rocas777/kairos"
+	"math"
+)
+
+// Domain declares the closed interval [Lo, Hi] where a function is defined, so that differentiators never evaluate it outside.
+// Near the edges of the domain, where the central stencil of a differentiator would cross them, a forward or backward stencil of the same
+// accuracy is used instead; if the domain is too narrow even for those, the step is reduced until the stencil fits.
+// Either bound can be infinite, like [0, +Inf) for math.Sqrt.
+//
+// Differentiating at a point outside the domain raises a panic.
+type Domain struct {
+	Lo float64
+	Hi float64
+}
+
+// NewDomain creates and returns a pointer to a new [Domain] covering the closed interval [lo, hi].
+//
+// If 'lo' is not lower than 'hi', a panic is raised.
+func NewDomain(lo, hi float64) *Domain {
+	d := &Domain{Lo: lo, Hi: hi}
+	d.handleInput()
+	return d
+}
+
+// Contains reports whether 'x' lies inside the domain.
+func (d *Domain) Contains(x float64) bool {
+	return x >= d.Lo && x <= d.Hi
+}
+
+// fits reports whether the points x + offsets[i]·h all lie inside the domain.
+func (d *Domain) fits(x, h float64, offsets []float64) bool {
+	for _, o := range offsets {
+		if !d.Contains(x + o*h) {
+			return false
+		}
+	}
+	return true
+}
+
+func (d *Domain) handleInput() {
+	if !(d.Lo < d.Hi) {
+		panic("Domain struct value of Lo should be lower than Hi")
+	}
+}
+
+// stencilRule applies a stencil of the derivative of order 'order' with step 'h'. When 'domain' is not nil, the central stencil is replaced
+// near its edges by the forward or backward stencil, whose weights are computed once.
+type stencilRule struct {
+	h      float64
+	order  uint
+	domain *Domain
+	// offsets and weights of the central, forward and backward stencils
+	offsets [3][]float64
+	weights [3][]float64
+}
+
+// newStencilRule returns a [stencilRule] around the 'central' offsets, whose one-sided replacements have an error of order h^accuracy.
+func newStencilRule(h float64, order uint, central []float64, accuracy uint, domain *Domain) *stencilRule {
+	r := &stencilRule{h: h, order: order, domain: domain}
+	r.offsets[0] = central
+	if domain != nil {
+		domain.handleInput()
+		r.offsets[1] = ForwardOffsets(order, accuracy)
+		r.offsets[2] = BackwardOffsets(order, accuracy)
+	}
+	for i, offsets := range r.offsets {
+		if offsets != nil {
+			r.weights[i] = Stencil(order, offsets)
+		}
+	}
+	return r
+}
+
+// derivative applies the stencil that fits inside the domain at 'x'.
+func (r *stencilRule) derivative(f func(x float64) float64, x float64) float64 {
+	if r.domain == nil {
+		return applyStencil(f, x, r.h, r.order, r.offsets[0], r.weights[0])
+	}
+	if !r.domain.Contains(x) {
+		panic("the point of the derivative should lie inside the Domain")
+	}
+	for i, offsets := range r.offsets {
+		if r.domain.fits(x, r.h, offsets) {
+			return applyStencil(f, x, r.h, r.order, offsets, r.weights[i])
+		}
+	}
+
+	// the domain is too narrow: shrink the step of the one-sided stencil with more room
+	i, room := 1, r.domain.Hi-x
+	if x-r.domain.Lo > room {
+		i, room = 2, x-r.domain.Lo
+	}
+	h := room / math.Abs(r.offsets[i][len(r.offsets[i])-1])
+	return applyStencil(f, x, h, r.order, r.offsets[i], r.weights[i])
+}
+
+// sweep applies the stencil at 'samples' points evenly spaced over [a, b].
+func (r *stencilRule) sweep(f func(x float64) float64, a, b float64, samples uint) []kairos.Pair {
+	if samples < 2 {
+		samples = 2
+	}
+	out := make([]kairos.Pair, samples)
+	sampleH := (b - a) / float64(samples-1)
+	for i := 0; i < int(samples); i++ {
+		x := a + float64(i)*sampleH
+		out[i] = kairos.Pair{X: x, Y: r.derivative(f, x)}
+	}
+	return out
+}
+
+// stencilAccuracy returns the order of the truncation error of the stencil of the derivative of order 'order' over 'offsets'.
+// Symmetric stencils gain one order, as their odd error terms cancel.
+func stencilAccuracy(order uint, offsets []float64) uint {
+	if uint(len(offsets)) <= order {
+		// not a valid stencil, which Stencil reports
+		return 1
+	}
+	accuracy := uint(len(offsets)) - order
+	if accuracy%2 == 1 && symmetric(offsets) {
+		accuracy++
+	}
+	return accuracy
+}
+
+// symmetric reports whether every offset has its opposite in 'offsets'.
+func symmetric(offsets []float64) bool {
+	for _, o := range offsets {
+		found := false
+		for _, p := range offsets {
+			if p == -o {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
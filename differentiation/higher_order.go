@@ -1,24 +1,46 @@
 // Package differentiation provides utilities for calculating derivatives of functions.
-// It supports the calculation of the first derivative using two algorithms: Simple (based on the regular definition)
-// and Symmetric (based on the symmetric definition). Additionally, it offers the HigherOrder method to calculate
-// arbitrary order derivatives. Users can choose the method that best fits their accuracy and efficiency requirements.
+// It supports the calculation of the first derivative using Simple (based on the regular definition),
+// Symmetric (based on the symmetric definition), ComplexStep (for functions of complex numbers, accurate to machine precision)
+// and Ridders' method, which extrapolates symmetric differences at shrinking steps and estimates its own error. Additionally, it offers the HigherOrder method to calculate
+// arbitrary order derivatives, and FiniteDifference, which applies finite-difference stencils of any order and accuracy generated by [Stencil].
+// Taylor computes the Taylor coefficients of functions of complex numbers to high orders with the Cauchy integral formula.
+// For functions of several variables, the Multivariate method calculates gradients, Jacobians, Hessians and directional derivatives.
+// Functions known only through samples, possibly noisy, are differentiated with SampledDerivative, SavitzkyGolay or TotalVariation.
+// Functions defined only on an interval can be given a [Domain], so that Symmetric, HigherOrder and FiniteDifference switch to one-sided
+// stencils near its edges. Users can choose the method that best fits their accuracy and efficiency requirements.
 //   - 1st order derivative based on the regular derivative definition [Simple]
 //   - 1st order derivative based on the symmetric derivative definition [Symmetric]
+//   - 1st order derivative with an error estimate using Richardson extrapolation [Ridders]
+//   - 1st order derivative of functions of complex numbers, without cancellation [ComplexStep]
 //   - nth order derivative based on the symmetric derivative definition [HigherOrder]
+//   - nth order derivative from central, one-sided or non-uniform stencils of arbitrary accuracy [FiniteDifference]
+//   - Taylor coefficients of high order with error estimates, using the Cauchy integral formula [Taylor]
+//   - gradient, Jacobian, Hessian and directional derivative of functions of several variables [Multivariate]
+//   - nth order derivative of unevenly sampled data using finite differences [SampledDerivative]
+//   - smoothing and nth order derivative of noisy sampled data using local polynomial fits [SavitzkyGolay]
+//   - 1st order derivative of noisy sampled data, preserving jumps, using total-variation regularization [TotalVariation]
+//
+// Every method that differentiates a function of a single variable implements the [Differentiator] interface, except for [ComplexStep] and [Taylor], whose functions take complex128 values.
 package differentiation
 
 import "github.com/rocas777/kairos"
 
 // HigherOrder contains methods for calculating the nth-order derivative, where the order is specified by the 'Order' field.
-// The algorithm used is the [Symmetric] algorithm. It achieves higher-order derivatives by recursively applying the first-order derivative.
+// The algorithm used is the [Symmetric] algorithm. It achieves higher-order derivatives by recursively applying the first-order derivative,
+// which amounts to a [Stencil] over the points x + k·H, for k = -Order, -Order+2, ..., Order. The stencil is applied directly,
+// so each derivative costs Order+1 evaluations of the function instead of 2^Order.
 // For more information about the algorithm and the use of the 'H' field, refer to [Symmetric].
 //
 // If 'H' is not specified, it defaults to 0.1. If 'H' is less than 0, a panic is raised.
 //
 // If 'Order' is not specified, it defaults to 1.
+//
+// If 'Domain' is specified, the function is only evaluated inside it: near its edges, forward or backward stencils of the same second order
+// accuracy are used instead. See [Domain].
 type HigherOrder struct {
-	H     float64
-	Order uint
+	H      float64
+	Order  uint
+	Domain *Domain
 }
 
 // NewHigherOrder creates and returns a pointer to the [HigherOrder] structure.
@@ -37,10 +59,16 @@ func NewHigherOrder(h float64, order uint) *HigherOrder {
 // It returns the calculated derivative value.
 func (s *HigherOrder) LocalDerivative(f func(x float64) float64, x float64) float64 {
 	s.handleInput()
-	if s.Order == 1 {
-		return NewSymmetric(s.H).LocalDerivative(f, x)
+	return s.rule().derivative(f, x)
+}
+
+// rule returns the stencil over the points reached by nesting 'Order' symmetric differences.
+func (s *HigherOrder) rule() *stencilRule {
+	offsets := make([]float64, s.Order+1)
+	for k := range offsets {
+		offsets[k] = float64(2*k - int(s.Order))
 	}
-	return (NewHigherOrder(s.H, s.Order-1).LocalDerivative(f, x+s.H) - NewHigherOrder(s.H, s.Order-1).LocalDerivative(f, x-s.H)) / (s.H * 2)
+	return newStencilRule(s.H, s.Order, offsets, 2, s.Domain)
 }
 
 func (s *HigherOrder) handleInput() {
@@ -59,14 +87,5 @@ func (s *HigherOrder) handleInput() {
 // This function is useful, for example, in drawing the line of the nth order derivative function of 'f'.
 func (s *HigherOrder) RangeDerivative(f func(x float64) float64, a, b float64, samples uint) []kairos.Pair {
 	s.handleInput()
-	if samples < 2 {
-		samples = 2
-	}
-	out := make([]kairos.Pair, samples)
-	sampleH := (b - a) / float64(samples-1)
-	for i := 0; i < int(samples); i++ {
-		x := a + float64(i)*sampleH
-		out[i] = kairos.Pair{X: x, Y: s.LocalDerivative(f, x)}
-	}
-	return out
+	return s.rule().sweep(f, a, b, samples)
 }
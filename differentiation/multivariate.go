@@ -0,0 +1,268 @@
+package differentiation
+
+import "math"
+
+// Scheme selects the finite differences used by [Multivariate].
+type Scheme int
+
+const (
+	// CentralScheme uses differences on both sides of the point, with an error of order h^2, at the cost of about twice the evaluations.
+	CentralScheme Scheme = iota
+	// ForwardScheme uses differences between the point and points after it, with an error of order h, reusing the value at the point.
+	ForwardScheme
+)
+
+// Multivariate provides methods for calculating the derivatives of functions of several variables: gradients, Jacobians, Hessians
+// and directional derivatives, using finite differences along each coordinate.
+//
+// The step along coordinate i is H·max(1, |x[i]|), so that it stays relative to the magnitude of each coordinate, and it is rounded
+// so that x[i]+step is exactly representable. 'H' balances the truncation error against cancellation; when it is not specified, the value
+// that balances both for a function computed to machine precision is used: about 1.5e-8 for forward differences, 6e-6 for central
+// differences and 1.2e-4 for the second differences of the Hessian.
+//
+// The slice passed to 'f' is reused between calls, so it must not be retained or modified.
+//
+// If 'H' is less than 0, a panic is raised.
+//
+// If 'Scheme' is not specified, it defaults to [CentralScheme].
+type Multivariate struct {
+	H      float64
+	Scheme Scheme
+	cycles uint
+}
+
+// NewMultivariate creates and returns a pointer to a new [Multivariate] instance with the specified relative step 'h' and 'scheme'.
+//
+// If 'h' is 0, the step is chosen for each kind of derivative. If 'h' is less than 0, a panic is raised.
+func NewMultivariate(h float64, scheme Scheme) *Multivariate {
+	if h < 0 {
+		panic("Multivariate struct value of H should be higher than 0")
+	}
+	return &Multivariate{H: h, Scheme: scheme}
+}
+
+// Cycles returns the number of evaluations of the function made by the last derivative.
+func (m *Multivariate) Cycles() uint {
+	return m.cycles
+}
+
+// Gradient calculates the gradient of the function 'f' at the point 'x', that is, its partial derivative with respect to every coordinate.
+// It costs len(x)+1 evaluations of 'f' with [ForwardScheme], and 2·len(x) with [CentralScheme].
+func (m *Multivariate) Gradient(f func(x []float64) float64, x []float64) []float64 {
+	m.handleInput()
+	m.cycles = 0
+	work := append([]float64(nil), x...)
+	eval := func() float64 {
+		m.cycles++
+		return f(work)
+	}
+
+	var fx float64
+	if m.Scheme == ForwardScheme {
+		fx = eval()
+	}
+	gradient := make([]float64, len(x))
+	for i := range x {
+		h := m.step(x[i], m.relative(false))
+		work[i] = x[i] + h
+		forward := eval()
+		if m.Scheme == ForwardScheme {
+			gradient[i] = (forward - fx) / h
+		} else {
+			work[i] = x[i] - h
+			gradient[i] = (forward - eval()) / (2 * h)
+		}
+		work[i] = x[i]
+	}
+	return gradient
+}
+
+// Jacobian calculates the Jacobian matrix of the vector function 'f' at the point 'x'. Row j holds the gradient of the output j,
+// so jacobian[j][i] is the partial derivative of output j with respect to coordinate i.
+// It costs len(x)+1 evaluations of 'f' with [ForwardScheme], and 2·len(x) with [CentralScheme].
+//
+// The slice returned by 'f' is copied, so 'f' may reuse it between calls. If 'f' returns slices of different lengths, a panic is raised.
+func (m *Multivariate) Jacobian(f func(x []float64) []float64, x []float64) [][]float64 {
+	m.handleInput()
+	m.cycles = 0
+	work := append([]float64(nil), x...)
+	outputs := -1
+	eval := func() []float64 {
+		m.cycles++
+		out := append([]float64(nil), f(work)...)
+		if outputs >= 0 && len(out) != outputs {
+			panic("the function of Multivariate Jacobian should always return the same number of outputs")
+		}
+		outputs = len(out)
+		return out
+	}
+
+	var fx []float64
+	if m.Scheme == ForwardScheme {
+		fx = eval()
+	}
+	var jacobian [][]float64
+	for i := range x {
+		h := m.step(x[i], m.relative(false))
+		work[i] = x[i] + h
+		forward := eval()
+		var backward []float64
+		scale := h
+		if m.Scheme == ForwardScheme {
+			backward = fx
+		} else {
+			work[i] = x[i] - h
+			backward = eval()
+			scale = 2 * h
+		}
+		work[i] = x[i]
+
+		if jacobian == nil {
+			jacobian = make([][]float64, len(forward))
+			for j := range jacobian {
+				jacobian[j] = make([]float64, len(x))
+			}
+		}
+		for j := range jacobian {
+			jacobian[j][i] = (forward[j] - backward[j]) / scale
+		}
+	}
+	return jacobian
+}
+
+// Hessian calculates the Hessian matrix of the function 'f' at the point 'x', that is, the matrix of its second partial derivatives.
+// The returned matrix is symmetric, as every mixed derivative is computed once.
+//
+// With [CentralScheme], it costs 2·len(x)² + 1 evaluations of 'f' and has an error of order h^2.
+// With [ForwardScheme], it costs (len(x)+1)(len(x)+2)/2 evaluations and has an error of order h.
+func (m *Multivariate) Hessian(f func(x []float64) float64, x []float64) [][]float64 {
+	m.handleInput()
+	m.cycles = 0
+	n := len(x)
+	work := append([]float64(nil), x...)
+	eval := func() float64 {
+		m.cycles++
+		return f(work)
+	}
+
+	steps := make([]float64, n)
+	for i := range steps {
+		steps[i] = m.step(x[i], m.relative(true))
+	}
+	hessian := make([][]float64, n)
+	for i := range hessian {
+		hessian[i] = make([]float64, n)
+	}
+	fx := eval()
+
+	if m.Scheme == ForwardScheme {
+		// single[i] holds f(x + h_i e_i)
+		single := make([]float64, n)
+		for i := range single {
+			work[i] = x[i] + steps[i]
+			single[i] = eval()
+			work[i] = x[i]
+		}
+		for i := 0; i < n; i++ {
+			for j := i; j < n; j++ {
+				work[i] += steps[i]
+				work[j] += steps[j]
+				hessian[i][j] = (eval() - single[i] - single[j] + fx) / (steps[i] * steps[j])
+				hessian[j][i] = hessian[i][j]
+				work[i], work[j] = x[i], x[j]
+			}
+		}
+		return hessian
+	}
+
+	for i := 0; i < n; i++ {
+		hi := steps[i]
+		work[i] = x[i] + hi
+		forward := eval()
+		work[i] = x[i] - hi
+		backward := eval()
+		work[i] = x[i]
+		hessian[i][i] = (forward - 2*fx + backward) / (hi * hi)
+
+		for j := i + 1; j < n; j++ {
+			hj := steps[j]
+			sum := 0.0
+			for _, sign := range [4][3]float64{{1, 1, 1}, {1, -1, -1}, {-1, 1, -1}, {-1, -1, 1}} {
+				work[i] = x[i] + sign[0]*hi
+				work[j] = x[j] + sign[1]*hj
+				sum += sign[2] * eval()
+			}
+			work[i], work[j] = x[i], x[j]
+			hessian[i][j] = sum / (4 * hi * hj)
+			hessian[j][i] = hessian[i][j]
+		}
+	}
+	return hessian
+}
+
+// DirectionalDerivative calculates the derivative of the function 'f' at the point 'x' along the direction 'v', that is,
+// the derivative of f(x + t·v) with respect to t at t = 0. It equals the dot product of the gradient with 'v', so 'v' is not normalized,
+// but it only costs 2 evaluations of 'f', whatever the number of coordinates.
+//
+// If 'x' and 'v' have different lengths, a panic is raised. If 'v' is zero, the derivative is 0.
+func (m *Multivariate) DirectionalDerivative(f func(x []float64) float64, x, v []float64) float64 {
+	m.handleInput()
+	if len(x) != len(v) {
+		panic("the point and the direction of Multivariate DirectionalDerivative should have the same length")
+	}
+	m.cycles = 0
+	var normX, normV float64
+	for i := range x {
+		normX = math.Max(normX, math.Abs(x[i]))
+		normV = math.Max(normV, math.Abs(v[i]))
+	}
+	if normV == 0 {
+		return 0
+	}
+
+	work := make([]float64, len(x))
+	eval := func(t float64) float64 {
+		for i := range work {
+			work[i] = x[i] + t*v[i]
+		}
+		m.cycles++
+		return f(work)
+	}
+	// the largest change of a coordinate is h·max(1, |x|), as with the other derivatives
+	h := m.relative(false) * math.Max(1, normX) / normV
+	if m.Scheme == ForwardScheme {
+		return (eval(h) - eval(0)) / h
+	}
+	return (eval(h) - eval(-h)) / (2 * h)
+}
+
+// relative returns the relative step, which is 'H' when specified, or the optimal one for the scheme otherwise.
+func (m *Multivariate) relative(second bool) float64 {
+	switch {
+	case m.H != 0:
+		return m.H
+	case second:
+		// the fourth root of the machine epsilon
+		return 1.2e-4
+	case m.Scheme == ForwardScheme:
+		// the square root of the machine epsilon
+		return 1.5e-8
+	}
+	// the cube root of the machine epsilon
+	return 6e-6
+}
+
+// step returns the step along a coordinate of value 'x', rounded so that x+step is exactly representable.
+func (m *Multivariate) step(x, relative float64) float64 {
+	h := relative * math.Max(1, math.Abs(x))
+	return (x + h) - x
+}
+
+func (m *Multivariate) handleInput() {
+	if m.H < 0 {
+		panic("Multivariate struct value of H should be higher than 0")
+	}
+	if m.Scheme != CentralScheme && m.Scheme != ForwardScheme {
+		panic("Multivariate struct value of Scheme is not valid")
+	}
+}
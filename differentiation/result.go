@@ -0,0 +1,12 @@
+package differentiation
+
+// Result holds the outcome of a differentiation that estimates its own error.
+//
+// 'Derivative' is the best estimate of the derivative, 'ErrorEstimate' is its estimated absolute error,
+// 'Step' is the step that produced it and 'Evaluations' is the number of calls made to the function.
+type Result struct {
+	Derivative    float64
+	ErrorEstimate float64
+	Step          float64
+	Evaluations   uint
+}
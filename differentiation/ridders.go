@@ -0,0 +1,104 @@
+package differentiation
+
+import (
+	"github.com/rocas777/kairos"
+	"math"
+)
+
+// Ridders provides methods for calculating the first derivative using [Ridders' method], which removes the need to hand-tune the step.
+// The [Symmetric] difference is evaluated at a sequence of steps, starting at 'H' and divided by 'Factor' each time, and the estimates are combined
+// into a Richardson extrapolation tableau. Each extrapolation removes the next even power of the step from the truncation error, so large steps,
+// which are unaffected by cancellation, can still give accurate results. The entry of the tableau with the smallest estimated error is returned,
+// and the process stops early once the error starts growing again, which happens when floating-point errors take over.
+//
+// If 'H' is not specified, it defaults to 0.1. If 'H' is less than 0, a panic is raised.
+//
+// If 'Factor' is not specified, it defaults to 1.4. If 'Factor' is not higher than 1, a panic is raised.
+//
+// If 'MaxSteps' is not specified, it defaults to 10.
+//
+// [Ridders' method]: https://doi.org/10.1016/S0141-1195(82)80057-0
+type Ridders struct {
+	H        float64
+	Factor   float64
+	MaxSteps uint
+}
+
+// NewRidders creates and returns a pointer to a new [Ridders] instance with the specified initial step 'h'.
+//
+// If 'h' is less than 0, a panic is raised.
+func NewRidders(h float64) *Ridders {
+	return &Ridders{H: h}
+}
+
+// LocalDerivative calculates the first order derivative of the function 'f' at the point 'x' using Ridders' method.
+// It returns the calculated derivative value.
+func (r *Ridders) LocalDerivative(f func(x float64) float64, x float64) float64 {
+	return r.LocalDerivativeResult(f, x).Derivative
+}
+
+// LocalDerivativeResult works like [Ridders.LocalDerivative] but returns a [Result] holding the derivative, its estimated error,
+// the step of the symmetric difference that produced it and the number of evaluations of 'f'.
+// When 'MaxSteps' is 1, no extrapolation is possible and the error is reported as +Inf.
+func (r *Ridders) LocalDerivativeResult(f func(x float64) float64, x float64) Result {
+	r.handleInput()
+
+	h := r.H
+	factor2 := r.Factor * r.Factor
+	// tableau[j] holds the j times extrapolated estimates of the previous and current steps
+	previous := []float64{(f(x+h) - f(x-h)) / (2 * h)}
+	out := Result{Derivative: previous[0], ErrorEstimate: math.Inf(1), Step: h, Evaluations: 2}
+	for i := 1; i < int(r.MaxSteps); i++ {
+		h /= r.Factor
+		current := make([]float64, i+1)
+		current[0] = (f(x+h) - f(x-h)) / (2 * h)
+		out.Evaluations += 2
+		scale := factor2
+		for j := 1; j <= i; j++ {
+			current[j] = (current[j-1]*scale - previous[j-1]) / (scale - 1)
+			scale *= factor2
+			err := math.Max(math.Abs(current[j]-current[j-1]), math.Abs(current[j]-previous[j-1]))
+			if err <= out.ErrorEstimate {
+				out.Derivative, out.ErrorEstimate, out.Step = current[j], err, h
+			}
+		}
+		// stop when the highest order estimate gets significantly worse
+		if math.Abs(current[i]-previous[i-1]) >= 2*out.ErrorEstimate {
+			break
+		}
+		previous = current
+	}
+	return out
+}
+
+func (r *Ridders) handleInput() {
+	if r.H == 0 {
+		r.H = 0.1
+	} else if r.H <= 0 {
+		panic("Ridders struct value of H should be higher than 0")
+	}
+	if r.Factor == 0 {
+		r.Factor = 1.4
+	} else if r.Factor <= 1 {
+		panic("Ridders struct value of Factor should be higher than 1")
+	}
+	if r.MaxSteps == 0 {
+		r.MaxSteps = 10
+	}
+}
+
+// RangeDerivative calculates the first order derivative of the function 'f' over the specified range [a, b] using the [Ridders] method.
+// It divides the range into 'samples' points and returns a slice of [kairos.Pair] representing the points and their corresponding derivative values.
+func (r *Ridders) RangeDerivative(f func(x float64) float64, a, b float64, samples uint) []kairos.Pair {
+	r.handleInput()
+	if samples < 2 {
+		samples = 2
+	}
+	out := make([]kairos.Pair, samples)
+	sampleH := (b - a) / float64(samples-1)
+	for i := 0; i < int(samples); i++ {
+		x := a + float64(i)*sampleH
+		out[i] = kairos.Pair{X: x, Y: r.LocalDerivative(f, x)}
+	}
+	return out
+}
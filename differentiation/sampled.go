@@ -0,0 +1,373 @@
+package differentiation
+
+import (
+	"github.com/rocas777/kairos"
+	"math"
+)
+
+// SampledDerivative calculates the derivative of order 'order' of a function known only through the samples in 'data',
+// such as measurements or the output of a RangeDerivative. At each sample, a [Stencil] is built over the order+2 nearest samples,
+// centered on it when possible and one-sided near the ends, so the samples may be unevenly spaced.
+// The result holds, for each sample, its X and the derivative there, so it can be plotted like the output of RangeDerivative.
+//
+// Finite differences amplify noise, roughly by the noise amplitude divided by the spacing to the power 'order'; for noisy data,
+// prefer [SavitzkyGolay] or [TotalVariation].
+//
+// If 'order' is not specified, it defaults to 1. The samples must be sorted by strictly increasing X, and there must be more samples
+// than 'order', otherwise a panic is raised.
+func SampledDerivative(data []kairos.Pair, order uint) []kairos.Pair {
+	if order == 0 {
+		order = 1
+	}
+	checkSamples(data, order+1)
+
+	points := int(order) + 2
+	if points > len(data) {
+		points = len(data)
+	}
+	out := make([]kairos.Pair, len(data))
+	offsets := make([]float64, points)
+	for i := range data {
+		start := window(i, points, len(data))
+		for j := range offsets {
+			offsets[j] = data[start+j].X - data[i].X
+		}
+		weights := Stencil(order, offsets)
+		out[i].X = data[i].X
+		for j, w := range weights {
+			out[i].Y += w * data[start+j].Y
+		}
+	}
+	return out
+}
+
+// SavitzkyGolay provides methods for smoothing and differentiating noisy sampled data with a [Savitzky-Golay] filter.
+// At each sample, a polynomial of degree 'Degree' is fitted by least squares to the 'Window' nearest samples, and its value or derivative
+// at the sample is returned. The fit averages the noise out, while polynomials of degree up to 'Degree' are reproduced exactly.
+//
+// For evenly spaced samples, this is the classic filter; unevenly spaced samples are fitted at their actual positions.
+// Near the ends, the window is shifted so that it stays inside the data, instead of padding the signal.
+// Wider windows and lower degrees remove more noise, but also flatten sharp features of the signal.
+//
+// If 'Window' is not specified, it defaults to 5. If it is even, or not higher than 'Degree', a panic is raised.
+//
+// If 'Degree' is not specified, it defaults to 2.
+//
+// If 'Order' is not specified, it defaults to 1. If it is higher than 'Degree', a panic is raised.
+//
+// [Savitzky-Golay]: https://en.wikipedia.org/wiki/Savitzky%E2%80%93Golay_filter
+type SavitzkyGolay struct {
+	Window uint
+	Degree uint
+	Order  uint
+}
+
+// NewSavitzkyGolay creates and returns a pointer to a new [SavitzkyGolay] instance that fits polynomials of degree 'degree'
+// over windows of 'window' samples, and calculates first order derivatives.
+//
+// If 'window' is even, or not higher than 'degree', a panic is raised.
+func NewSavitzkyGolay(window, degree uint) *SavitzkyGolay {
+	return &SavitzkyGolay{Window: window, Degree: degree}
+}
+
+// SampledDerivative calculates the derivative of order 'Order' of the function sampled in 'data', using the Savitzky-Golay filter.
+// The result holds, for each sample, its X and the derivative there.
+//
+// The samples must be sorted by strictly increasing X, and there must be more samples than 'Degree', otherwise a panic is raised.
+func (s *SavitzkyGolay) SampledDerivative(data []kairos.Pair) []kairos.Pair {
+	s.handleInput()
+	return s.filter(data, s.Order)
+}
+
+// Smooth returns the samples in 'data' smoothed by the Savitzky-Golay filter, that is, the value of each fitted polynomial at its sample.
+//
+// The samples must be sorted by strictly increasing X, and there must be more samples than 'Degree', otherwise a panic is raised.
+func (s *SavitzkyGolay) Smooth(data []kairos.Pair) []kairos.Pair {
+	s.handleInput()
+	return s.filter(data, 0)
+}
+
+func (s *SavitzkyGolay) filter(data []kairos.Pair, order uint) []kairos.Pair {
+	checkSamples(data, s.Degree+1)
+
+	points := int(s.Window)
+	if points > len(data) {
+		points = len(data)
+	}
+	terms := int(s.Degree) + 1
+	factorial := 1.0
+	for k := 2; k <= int(order); k++ {
+		factorial *= float64(k)
+	}
+	out := make([]kairos.Pair, len(data))
+	powers := make([]float64, terms)
+	normal := make([][]float64, terms)
+	for k := range normal {
+		normal[k] = make([]float64, terms+1)
+	}
+	for i := range data {
+		start := window(i, points, len(data))
+		// the offsets are scaled to [-1, 1] to keep the normal equations well conditioned
+		scale := math.Max(data[i].X-data[start].X, data[start+points-1].X-data[i].X)
+		for k := range normal {
+			for l := range normal[k] {
+				normal[k][l] = 0
+			}
+		}
+		for j := start; j < start+points; j++ {
+			t := (data[j].X - data[i].X) / scale
+			powers[0] = 1
+			for k := 1; k < terms; k++ {
+				powers[k] = powers[k-1] * t
+			}
+			for k := 0; k < terms; k++ {
+				for l := 0; l < terms; l++ {
+					normal[k][l] += powers[k] * powers[l]
+				}
+				normal[k][terms] += powers[k] * data[j].Y
+			}
+		}
+		coefficients := solveLinear(normal)
+		out[i] = kairos.Pair{X: data[i].X, Y: factorial * coefficients[order] / math.Pow(scale, float64(order))}
+	}
+	return out
+}
+
+func (s *SavitzkyGolay) handleInput() {
+	if s.Window == 0 {
+		s.Window = 5
+	}
+	if s.Degree == 0 {
+		s.Degree = 2
+	}
+	if s.Order == 0 {
+		s.Order = 1
+	}
+	if s.Window%2 == 0 {
+		panic("SavitzkyGolay struct value of Window should be odd")
+	}
+	if s.Window <= s.Degree {
+		panic("SavitzkyGolay struct value of Window should be higher than Degree")
+	}
+	if s.Order > s.Degree {
+		panic("SavitzkyGolay struct value of Order should not be higher than Degree")
+	}
+}
+
+// TotalVariation provides a method for differentiating noisy sampled data with [total-variation regularization].
+// The derivative u is the minimizer of
+//
+//	Alpha·Σ|u[i+1]-u[i]| + ½·Σ(∫u - (y - y[0]))²
+//
+// that is, the derivative whose integral best matches the data, penalized by its total variation. Unlike smoothing filters,
+// the penalty allows jumps, so derivatives of piecewise smooth signals keep their discontinuities while the noise is suppressed.
+// Larger values of 'Alpha' give flatter derivatives, and smooth derivatives become staircases when it is too large.
+// As it depends on the scale of the data and on the noise, it is usually tuned by increasing it until the derivative stops following the noise.
+//
+// The problem is solved by the lagged diffusivity fixed point iteration, starting from [SampledDerivative]; each of its 'Iterations'
+// solves a linear system with the conjugate gradient method, so the cost grows with the square of the number of samples.
+//
+// If 'Alpha' is not higher than 0, a panic is raised.
+//
+// If 'Iterations' is not specified, it defaults to 20.
+//
+// [total-variation regularization]: https://doi.org/10.5402/2011/164564
+type TotalVariation struct {
+	Alpha      float64
+	Iterations uint
+}
+
+// NewTotalVariation creates and returns a pointer to a new [TotalVariation] instance with the regularization weight 'alpha'.
+//
+// If 'alpha' is not higher than 0, a panic is raised.
+func NewTotalVariation(alpha float64) *TotalVariation {
+	return &TotalVariation{Alpha: alpha}
+}
+
+// SampledDerivative calculates the first order derivative of the function sampled in 'data', using total-variation regularization.
+// The result holds, for each sample, its X and the derivative there.
+//
+// The samples must be sorted by strictly increasing X, and there must be at least 2 samples, otherwise a panic is raised.
+func (t *TotalVariation) SampledDerivative(data []kairos.Pair) []kairos.Pair {
+	t.handleInput()
+	checkSamples(data, 2)
+
+	n := len(data)
+	initial := SampledDerivative(data, 1)
+	u := make([]float64, n)
+	target := make([]float64, n)
+	for i := range data {
+		u[i] = initial[i].Y
+		target[i] = data[i].Y - data[0].Y
+	}
+
+	// integrate returns the cumulative trapezoidal integral of 'v', and integrateT applies its transpose
+	integrate := func(v, out []float64) {
+		out[0] = 0
+		for i := 1; i < n; i++ {
+			out[i] = out[i-1] + (data[i].X-data[i-1].X)*(v[i-1]+v[i])/2
+		}
+	}
+	integrateT := func(v, out []float64) {
+		// tail holds the sum of v[k] for k > i
+		tail := 0.0
+		for i := n - 1; i >= 0; i-- {
+			out[i] = 0
+			if i < n-1 {
+				out[i] += (data[i+1].X - data[i].X) / 2 * tail
+			}
+			if i > 0 {
+				out[i] += (data[i].X - data[i-1].X) / 2 * (tail + v[i])
+			}
+			tail += v[i]
+		}
+	}
+	// the smoothing of |·| keeps the problem differentiable; it is scaled with the initial jumps
+	epsilon := 0.0
+	for i := 1; i < n; i++ {
+		epsilon = math.Max(epsilon, math.Abs(u[i]-u[i-1]))
+	}
+	epsilon = 1e-12*epsilon*epsilon + math.SmallestNonzeroFloat64
+
+	weights := make([]float64, n-1)
+	// operator applies Alpha·Dᵀ·diag(weights)·D + AᵀA, where D takes differences and A integrates
+	scratch, integral := make([]float64, n), make([]float64, n)
+	operator := func(v, out []float64) {
+		integrate(v, integral)
+		integrateT(integral, out)
+		for i := range weights {
+			flux := t.Alpha * weights[i] * (v[i+1] - v[i])
+			out[i] -= flux
+			out[i+1] += flux
+		}
+	}
+
+	gradient := make([]float64, n)
+	step := make([]float64, n)
+	for iteration := 0; iteration < int(t.Iterations); iteration++ {
+		for i := range weights {
+			d := u[i+1] - u[i]
+			weights[i] = 1 / math.Sqrt(d*d+epsilon)
+		}
+		// gradient of the objective: operator(u) - Aᵀ(y - y[0])
+		operator(u, gradient)
+		integrateT(target, scratch)
+		for i := range gradient {
+			gradient[i] -= scratch[i]
+		}
+		if !conjugateGradient(operator, gradient, step, n) {
+			break
+		}
+		for i := range u {
+			u[i] -= step[i]
+		}
+	}
+
+	out := make([]kairos.Pair, n)
+	for i := range data {
+		out[i] = kairos.Pair{X: data[i].X, Y: u[i]}
+	}
+	return out
+}
+
+func (t *TotalVariation) handleInput() {
+	if t.Alpha <= 0 {
+		panic("TotalVariation struct value of Alpha should be higher than 0")
+	}
+	if t.Iterations == 0 {
+		t.Iterations = 20
+	}
+}
+
+// conjugateGradient solves operator(x) = b for a symmetric positive definite operator, with at most 'maxIterations' iterations,
+// starting from x = 0. It returns false if 'b' is already zero, in which case 'x' is left untouched.
+func conjugateGradient(operator func(v, out []float64), b, x []float64, maxIterations int) bool {
+	n := len(b)
+	residual := append([]float64(nil), b...)
+	direction := append([]float64(nil), b...)
+	product := make([]float64, n)
+	rr := dot(residual, residual)
+	if rr == 0 {
+		return false
+	}
+	for i := range x {
+		x[i] = 0
+	}
+	tolerance := 1e-20 * rr
+	for iteration := 0; iteration < maxIterations && rr > tolerance; iteration++ {
+		operator(direction, product)
+		alpha := rr / dot(direction, product)
+		for i := range x {
+			x[i] += alpha * direction[i]
+			residual[i] -= alpha * product[i]
+		}
+		next := dot(residual, residual)
+		for i := range direction {
+			direction[i] = residual[i] + next/rr*direction[i]
+		}
+		rr = next
+	}
+	return true
+}
+
+func dot(a, b []float64) float64 {
+	out := 0.0
+	for i := range a {
+		out += a[i] * b[i]
+	}
+	return out
+}
+
+// solveLinear solves the linear system whose augmented matrix is 'm', by Gaussian elimination with partial pivoting.
+// The matrix is overwritten.
+func solveLinear(m [][]float64) []float64 {
+	n := len(m)
+	for col := 0; col < n; col++ {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if math.Abs(m[row][col]) > math.Abs(m[pivot][col]) {
+				pivot = row
+			}
+		}
+		m[col], m[pivot] = m[pivot], m[col]
+		for row := col + 1; row < n; row++ {
+			factor := m[row][col] / m[col][col]
+			for k := col; k <= n; k++ {
+				m[row][k] -= factor * m[col][k]
+			}
+		}
+	}
+	x := make([]float64, n)
+	for row := n - 1; row >= 0; row-- {
+		sum := m[row][n]
+		for k := row + 1; k < n; k++ {
+			sum -= m[row][k] * x[k]
+		}
+		x[row] = sum / m[row][row]
+	}
+	return x
+}
+
+// window returns the start of the 'points' consecutive samples, out of 'n', that are centered on sample 'i' when possible.
+func window(i, points, n int) int {
+	start := i - (points-1)/2
+	if start > n-points {
+		start = n - points
+	}
+	if start < 0 {
+		start = 0
+	}
+	return start
+}
+
+// checkSamples panics if 'data' has fewer than 'least' samples or is not sorted by strictly increasing X.
+func checkSamples(data []kairos.Pair, least uint) {
+	if len(data) < int(least) {
+		panic("there are not enough samples to calculate the derivative")
+	}
+	for i := 1; i < len(data); i++ {
+		if data[i].X <= data[i-1].X {
+			panic("sampled data should be sorted by strictly increasing X")
+		}
+	}
+}
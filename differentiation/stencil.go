@@ -0,0 +1,205 @@
+package differentiation
+
+import (
+	"github.com/rocas777/kairos"
+	"math"
+)
+
+// Stencil returns the weights of the finite difference that approximates the derivative of order 'order' at a point 'x'
+// from the values of a function at the points x + offsets[i]·h. The derivative is the sum of weights[i]·f(x + offsets[i]·h), divided by h^order.
+// The weights are computed with [Fornberg's algorithm], so the offsets may be central, one-sided or non-uniformly spaced, and in any order.
+// A stencil of n points is exact for polynomials of degree n-1, and its truncation error is of order h^(n-order) in general.
+//
+// If 'order' is not lower than the number of offsets, or if two offsets are equal, a panic is raised.
+//
+// [Fornberg's algorithm]: https://doi.org/10.1090/S0025-5718-1988-0935077-0
+func Stencil(order uint, offsets []float64) []float64 {
+	n := len(offsets)
+	m := int(order)
+	if m >= n {
+		panic("the number of offsets of a Stencil should be higher than its order")
+	}
+
+	// c[i][k] is the weight of offsets[i] in the derivative of order k, using the points seen so far
+	c := make([][]float64, n)
+	for i := range c {
+		c[i] = make([]float64, m+1)
+	}
+	c[0][0] = 1
+	c1 := 1.0
+	c4 := offsets[0]
+	for i := 1; i < n; i++ {
+		top := i
+		if m < top {
+			top = m
+		}
+		c2 := 1.0
+		c5 := c4
+		c4 = offsets[i]
+		for j := 0; j < i; j++ {
+			c3 := offsets[i] - offsets[j]
+			if c3 == 0 {
+				panic("the offsets of a Stencil should be distinct")
+			}
+			c2 *= c3
+			if j == i-1 {
+				for k := top; k >= 1; k-- {
+					c[i][k] = c1 * (float64(k)*c[i-1][k-1] - c5*c[i-1][k]) / c2
+				}
+				c[i][0] = -c1 * c5 * c[i-1][0] / c2
+			}
+			for k := top; k >= 1; k-- {
+				c[j][k] = (c4*c[j][k] - float64(k)*c[j][k-1]) / c3
+			}
+			c[j][0] = c4 * c[j][0] / c3
+		}
+		c1 = c2
+	}
+
+	weights := make([]float64, n)
+	for i := range weights {
+		weights[i] = c[i][m]
+	}
+	return weights
+}
+
+// CentralOffsets returns the smallest symmetric set of integer offsets whose [Stencil] approximates the derivative of order 'order'
+// with a truncation error of order h^accuracy. Central stencils only reach even accuracies, so an odd 'accuracy' is rounded up.
+//
+// If 'order' is not specified, it defaults to 1. If 'accuracy' is not specified, it defaults to 2.
+func CentralOffsets(order, accuracy uint) []float64 {
+	order, accuracy = stencilDefaults(order, accuracy)
+	accuracy += accuracy % 2
+	half := int((order+1)/2 - 1 + accuracy/2)
+	offsets := make([]float64, 2*half+1)
+	for i := range offsets {
+		offsets[i] = float64(i - half)
+	}
+	return offsets
+}
+
+// ForwardOffsets returns the offsets 0, 1, 2, ..., whose [Stencil] approximates the derivative of order 'order'
+// with a truncation error of order h^accuracy, using only points at or after 'x'.
+//
+// If 'order' is not specified, it defaults to 1. If 'accuracy' is not specified, it defaults to 2.
+func ForwardOffsets(order, accuracy uint) []float64 {
+	order, accuracy = stencilDefaults(order, accuracy)
+	offsets := make([]float64, order+accuracy)
+	for i := range offsets {
+		offsets[i] = float64(i)
+	}
+	return offsets
+}
+
+// BackwardOffsets returns the offsets 0, -1, -2, ..., whose [Stencil] approximates the derivative of order 'order'
+// with a truncation error of order h^accuracy, using only points at or before 'x'.
+//
+// If 'order' is not specified, it defaults to 1. If 'accuracy' is not specified, it defaults to 2.
+func BackwardOffsets(order, accuracy uint) []float64 {
+	offsets := ForwardOffsets(order, accuracy)
+	for i := range offsets {
+		offsets[i] = -offsets[i]
+	}
+	return offsets
+}
+
+func stencilDefaults(order, accuracy uint) (uint, uint) {
+	if order == 0 {
+		order = 1
+	}
+	if accuracy == 0 {
+		accuracy = 2
+	}
+	return order, accuracy
+}
+
+// FiniteDifference provides methods for calculating the derivative of order 'Order' by applying the weights of a [Stencil]
+// to the values of the function at the points x + Offsets[i]·H. The stencil is computed once per call, and every point is evaluated once,
+// so a stencil of n points costs n evaluations of the function. Points whose weight is zero, like the center of central first derivatives,
+// are not evaluated.
+//
+// 'Offsets' selects the points of the stencil, and can be built with [CentralOffsets], [ForwardOffsets] and [BackwardOffsets], or given
+// by hand for non-uniform stencils. One-sided stencils are useful when the function is not defined on one side of 'x'.
+//
+// If 'H' is not specified, it defaults to 0.1. If 'H' is less than 0, a panic is raised.
+//
+// If 'Order' is not specified, it defaults to 1.
+//
+// If 'Offsets' is not specified, it defaults to the central stencil of second order accuracy, CentralOffsets(Order, 2).
+// If it has no more points than 'Order', a panic is raised.
+//
+// If 'Domain' is specified, the function is only evaluated inside it: where the stencil would cross its edges, a forward or backward stencil
+// of the same accuracy is used instead. See [Domain].
+type FiniteDifference struct {
+	H       float64
+	Order   uint
+	Offsets []float64
+	Domain  *Domain
+}
+
+// NewFiniteDifference creates and returns a pointer to a new [FiniteDifference] instance with the specified value of 'h',
+// derivative order 'order' and stencil 'offsets'.
+//
+// If 'h' is less than 0, a panic is raised.
+func NewFiniteDifference(h float64, order uint, offsets []float64) *FiniteDifference {
+	if h < 0 {
+		panic("FiniteDifference struct value of H should be higher than 0")
+	}
+	return &FiniteDifference{H: h, Order: order, Offsets: offsets}
+}
+
+// Weights returns the weights of the stencil that [FiniteDifference.LocalDerivative] applies to the values at the points x + Offsets[i]·H,
+// before dividing by H^Order.
+func (s *FiniteDifference) Weights() []float64 {
+	s.handleInput()
+	return Stencil(s.Order, s.offsets())
+}
+
+// LocalDerivative calculates the derivative of order 'Order' of the function 'f' at the point 'x' using the FiniteDifference method.
+// It returns the calculated derivative value.
+func (s *FiniteDifference) LocalDerivative(f func(x float64) float64, x float64) float64 {
+	s.handleInput()
+	return s.rule().derivative(f, x)
+}
+
+// RangeDerivative calculates the derivative of order 'Order' of the function 'f' over the specified range [a, b] using the FiniteDifference method.
+// It divides the range into 'samples' points and returns a slice of [kairos.Pair] representing the points and their corresponding derivative values.
+// The stencils are computed once and shared by every point.
+func (s *FiniteDifference) RangeDerivative(f func(x float64) float64, a, b float64, samples uint) []kairos.Pair {
+	s.handleInput()
+	return s.rule().sweep(f, a, b, samples)
+}
+
+func (s *FiniteDifference) offsets() []float64 {
+	if s.Offsets == nil {
+		return CentralOffsets(s.Order, 2)
+	}
+	return s.Offsets
+}
+
+func (s *FiniteDifference) rule() *stencilRule {
+	offsets := s.offsets()
+	return newStencilRule(s.H, s.Order, offsets, stencilAccuracy(s.Order, offsets), s.Domain)
+}
+
+func (s *FiniteDifference) handleInput() {
+	if s.H == 0 {
+		s.H = 0.1
+	} else if s.H <= 0 {
+		panic("FiniteDifference struct value of H should be higher than 0")
+	}
+	if s.Order == 0 {
+		s.Order = 1
+	}
+}
+
+// applyStencil returns the sum of weights[i]·f(x + offsets[i]·h), divided by h^order, skipping the points of weight zero.
+func applyStencil(f func(x float64) float64, x, h float64, order uint, offsets, weights []float64) float64 {
+	out := 0.0
+	for i, w := range weights {
+		if w != 0 {
+			out += w * f(x+offsets[i]*h)
+		}
+	}
+	return out / math.Pow(h, float64(order))
+}
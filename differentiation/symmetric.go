@@ -13,9 +13,13 @@ import "github.com/rocas777/kairos"
 //
 // If 'Order' is not specified, it defaults to 1.
 //
+// If 'Domain' is specified, the function is only evaluated inside it: near its edges, the forward or backward difference of the same
+// second order accuracy, over 'x', 'x ± H' and 'x ± 2H', is used instead. See [Domain].
+//
 // [definition]: https://en.wikipedia.org/wiki/Symmetric_derivative
 type Symmetric struct {
-	H float64
+	H      float64
+	Domain *Domain
 }
 
 // NewSymmetric creates and returns a pointer to a new Symmetric instance with the specified value of 'h'.
@@ -29,7 +33,14 @@ func NewSymmetric(h float64) *Symmetric {
 // It returns the calculated derivative value.
 func (s *Symmetric) LocalDerivative(f func(x float64) float64, x float64) float64 {
 	s.handleInput()
-	return (f(x+s.H) - f(x-s.H)) / (s.H * 2)
+	if s.Domain == nil {
+		return (f(x+s.H) - f(x-s.H)) / (s.H * 2)
+	}
+	return s.rule().derivative(f, x)
+}
+
+func (s *Symmetric) rule() *stencilRule {
+	return newStencilRule(s.H, 1, []float64{-1, 1}, 2, s.Domain)
 }
 
 func (s *Symmetric) handleInput() {
@@ -45,14 +56,5 @@ func (s *Symmetric) handleInput() {
 // This function is useful, for example, in drawing the line of the first derivative function of 'f'.
 func (s *Symmetric) RangeDerivative(f func(x float64) float64, a, b float64, samples uint) []kairos.Pair {
 	s.handleInput()
-	if samples < 2 {
-		samples = 2
-	}
-	out := make([]kairos.Pair, samples)
-	sampleH := (b - a) / float64(samples-1)
-	for i := 0; i < int(samples); i++ {
-		x := a + float64(i)*sampleH
-		out[i] = kairos.Pair{X: x, Y: s.LocalDerivative(f, x)}
-	}
-	return out
+	return s.rule().sweep(f, a, b, samples)
 }
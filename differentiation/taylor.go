@@ -0,0 +1,179 @@
+package differentiation
+
+import (
+	"github.com/rocas777/kairos/internal/fourier"
+	"math"
+	"math/bits"
+	"math/cmplx"
+)
+
+// Taylor provides methods for calculating the first Taylor coefficients of a function at a point, that is, the a[n] = f⁽ⁿ⁾(x)/n!
+// of its expansion Σ a[n]·(z-x)^n, using the [Cauchy integral formula]. The function is sampled at 'Points' points evenly spaced
+// on a circle of radius 'Radius' around 'x' in the complex plane, and a fast Fourier transform of the samples gives every coefficient at once.
+// Unlike nested finite differences, whose errors grow quickly with the order, the coefficients stay accurate to high orders,
+// which makes them suitable to build local polynomial expansions.
+//
+// As with [ComplexStep], 'f' must be analytic inside the circle and be written with operations that extend it to complex numbers,
+// such as those of the math/cmplx package.
+//
+// Each coefficient comes with an error estimate: the difference with the coefficient computed from every other sample, which is usually
+// pessimistic, plus the rounding error, which grows as Radius^-n. Large radii suffer from aliasing, while small radii amplify rounding errors.
+// A circle that encloses a singularity of 'f' gives wrong coefficients that the estimates do not always reveal, so a fixed 'Radius'
+// should stay below the distance to the nearest singularity. If 'Radius' is not specified, circles from 16·max(1, |x|) down to
+// 2^-20·max(1, |x|), halving the radius each time, are tried, and the one that minimizes the geometric mean of the relative errors is kept.
+// Circles where 'f' is not finite, for instance because it overflows, are only kept when every circle is like them.
+//
+// If 'Radius' is less than 0, a panic is raised.
+//
+// If 'Points' is not specified, it defaults to the smallest power of 2 not lower than 4·k, and at least 32. If it is not a power of 2,
+// or lower than 2·k, a panic is raised.
+//
+// [Cauchy integral formula]: https://en.wikipedia.org/wiki/Cauchy%27s_integral_formula
+type Taylor struct {
+	Radius float64
+	Points uint
+}
+
+// Series holds the first Taylor coefficients of a function at 'Center', as computed by [Taylor].
+//
+// 'Coefficients' holds a[n] = f⁽ⁿ⁾(Center)/n!, 'ErrorEstimates' holds their estimated absolute errors, 'Radius' is the radius of
+// the circle that produced them and 'Evaluations' is the number of calls made to the function.
+type Series struct {
+	Center         float64
+	Coefficients   []float64
+	ErrorEstimates []float64
+	Radius         float64
+	Evaluations    uint
+}
+
+// NewTaylor creates and returns a pointer to a new [Taylor] instance with the specified circle 'radius' and number of 'points'.
+//
+// If 'radius' is 0, it is chosen automatically. If 'radius' is less than 0, a panic is raised.
+func NewTaylor(radius float64, points uint) *Taylor {
+	if radius < 0 {
+		panic("Taylor struct value of Radius should be higher than 0")
+	}
+	return &Taylor{Radius: radius, Points: points}
+}
+
+// Coefficients calculates the first 'k' Taylor coefficients of the function 'f' at the point 'x', from a[0] = f(x) to a[k-1].
+//
+// If 'k' is 0, it defaults to 1.
+func (t *Taylor) Coefficients(f func(z complex128) complex128, x float64, k uint) []float64 {
+	return t.Series(f, x, k).Coefficients
+}
+
+// Series works like [Taylor.Coefficients] but returns a [Series] holding the coefficients together with their estimated errors,
+// the radius of the circle and the number of evaluations of 'f'.
+func (t *Taylor) Series(f func(z complex128) complex128, x float64, k uint) Series {
+	if k == 0 {
+		k = 1
+	}
+	points := t.points(k)
+
+	if t.Radius != 0 {
+		s, _ := taylorCircle(f, x, t.Radius, k, points)
+		s.Evaluations = points
+		return s
+	}
+
+	scale := math.Max(1, math.Abs(x))
+	var best Series
+	bestScore := math.Inf(1)
+	evaluations := uint(0)
+	for m := 4; m >= -20; m-- {
+		s, rounding := taylorCircle(f, x, scale*math.Ldexp(1, m), k, points)
+		evaluations += points
+		// the logarithm of the geometric mean of the relative errors, where the error of a coefficient smaller than
+		// its rounding error is measured against the rounding error instead
+		score := 0.0
+		for n, e := range s.ErrorEstimates {
+			score += math.Log(e / math.Max(math.Abs(s.Coefficients[n]), rounding[n]))
+		}
+		if math.IsNaN(score) {
+			// 'f' is not finite on the circle, so any circle where it is should be preferred
+			score = math.Inf(1)
+		}
+		if score < bestScore || best.Coefficients == nil {
+			best, bestScore = s, score
+		}
+	}
+	best.Evaluations = evaluations
+	return best
+}
+
+// points returns the number of points of the circle for 'k' coefficients, after validating 'Radius' and 'Points'.
+func (t *Taylor) points(k uint) uint {
+	if t.Radius < 0 {
+		panic("Taylor struct value of Radius should be higher than 0")
+	}
+	if t.Points == 0 {
+		if points := uint(1) << bits.Len(4*k-1); points > 32 {
+			return points
+		}
+		return 32
+	}
+	if t.Points&(t.Points-1) != 0 {
+		panic("Taylor struct value of Points should be a power of 2")
+	}
+	if t.Points < 2*k {
+		panic("Taylor struct value of Points should not be lower than twice the number of coefficients")
+	}
+	return t.Points
+}
+
+// Evaluate returns the value of the truncated series at 'x', which approximates the function near 'Center'.
+func (s Series) Evaluate(x float64) float64 {
+	out := 0.0
+	for n := len(s.Coefficients) - 1; n >= 0; n-- {
+		out = out*(x-s.Center) + s.Coefficients[n]
+	}
+	return out
+}
+
+// Derivative returns the derivative of order 'n' at 'Center', that is, n!·Coefficients[n].
+// If 'n' is not lower than the number of coefficients, a panic is raised.
+func (s Series) Derivative(n uint) float64 {
+	if n >= uint(len(s.Coefficients)) {
+		panic("the Series has no coefficient of the requested order")
+	}
+	factorial := 1.0
+	for i := 2; i <= int(n); i++ {
+		factorial *= float64(i)
+	}
+	return factorial * s.Coefficients[n]
+}
+
+// taylorCircle computes the first 'k' coefficients from 'points' samples on the circle of radius 'r' around 'x', along with their rounding errors.
+// The error estimates compare them with the coefficients given by the even samples, and include the rounding error.
+// If 'f' is not finite on the circle, every error estimate is +Inf.
+func taylorCircle(f func(z complex128) complex128, x, r float64, k, points uint) (Series, []float64) {
+	values := make([]complex128, points)
+	size := 0.0
+	for j := range values {
+		values[j] = f(complex(x, 0) + cmplx.Rect(r, 2*math.Pi*float64(j)/float64(points)))
+		size = math.Max(size, cmplx.Abs(values[j]))
+	}
+	coarse := make([]complex128, points/2)
+	for j := range coarse {
+		coarse[j] = values[2*j]
+	}
+	fourier.FFT(values)
+	fourier.FFT(coarse)
+
+	s := Series{Center: x, Coefficients: make([]float64, k), ErrorEstimates: make([]float64, k), Radius: r}
+	rounding := make([]float64, k)
+	scale := 1.0
+	for n := range s.Coefficients {
+		fine := real(values[n]) / float64(points) / scale
+		// about the machine epsilon times the largest sample, carried to the coefficient
+		rounding[n] = 2.2e-16*size/scale + math.SmallestNonzeroFloat64
+		s.Coefficients[n] = fine
+		s.ErrorEstimates[n] = math.Abs(fine-real(coarse[n])/float64(points/2)/scale) + rounding[n]
+		if math.IsNaN(s.ErrorEstimates[n]) || math.IsInf(size, 0) {
+			s.ErrorEstimates[n] = math.Inf(1)
+		}
+		scale *= r
+	}
+	return s, rounding
+}
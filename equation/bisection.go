@@ -1,16 +1,23 @@
 // Package equation provides utilities for solving equations and finding the roots of functions.
-// It offers multiple root-finding methods, including the Bisection method, False Position method,
+// It offers multiple root-finding methods, including the Bisection method, Brent's method, False Position method,
 // Newton-Raphson method, and Secant method. Users can choose the most suitable method for their
 // specific functions and interval constraints to efficiently locate zeros of the given function.
 //   - [Bisection]
+//   - [Brent]
 //   - [FalsePosition]
 //   - [NewtonRaphson]
 //   - [Secant]
 //
+// The methods starting from two points implement the [Solver] interface, while [NewtonRaphson] implements [DerivativeSolver].
+// [NewtonRaphson] can also take a function written against autodiff.Dual numbers, whose derivative is then computed exactly.
+//
 // Note: These methods assume the provided function is continuous on the considered interval.
 package equation
 
-import "math"
+import (
+	"fmt"
+	"math"
+)
 
 // Bisection provides a method to find the zero of a function using the [bisection] method on an interval [a, b].
 // The method can be limited by CycleLimit, which restricts the number of cycles to prevent the algorithm from running indefinitely.
@@ -43,36 +50,65 @@ func (s *Bisection) Cycles() uint {
 // Zero finds the zero of the function 'f' using the [Bisection] method on the interval [a, b].
 // It iteratively narrows down the interval until the solution is found within the specified precision ('Epsilon') or until the maximum number of cycles ('CycleLimit') is reached.
 // The result is returned as a float64. If no zero is found within the given constraints, it returns math.NaN().
+// Use [Bisection.ZeroResult] to know why no zero was found.
 //
 // Note: The function 'f' must have a zero on the interval [a, b], and it must be continuous on that interval.
 func (s *Bisection) Zero(f func(x float64) float64, a, b float64) float64 {
-	s.handleInput()
-	var c float64
+	return rootOrNaN(s.ZeroResult(f, a, b))
+}
+
+// ZeroResult works like [Bisection.Zero] but returns a [Result] describing the run together with an error.
+// The error is [ErrNoSignChange] if 'f' does not change sign on [a, b], [ErrNonFinite] if 'f' returns NaN or ±Inf,
+// [ErrMaxIterations] if 'CycleLimit' is reached and [ErrInvalidConfig] if the struct is misconfigured.
+func (s *Bisection) ZeroResult(f func(x float64) float64, a, b float64) (Result, error) {
+	s.cycles = 0
+	if err := s.handleInput(); err != nil {
+		return Result{Root: math.NaN()}, err
+	}
+	ev := evaluator{f: f}
+	fa := ev.eval(a)
+	fb := ev.eval(b)
+	if !finite(fa) || !finite(fb) {
+		return ev.result(a, fa, b-a, s.cycles), ErrNonFinite
+	}
+	if fa*fb > 0 {
+		return ev.result(a, fa, b-a, s.cycles), ErrNoSignChange
+	}
+	if fa == 0 {
+		return ev.result(a, fa, b-a, s.cycles), nil
+	}
+	if fb == 0 {
+		return ev.result(b, fb, b-a, s.cycles), nil
+	}
+	var c, yc float64
 	for s.cycles < s.CycleLimit {
 		c = (a + b) / 2
-		yc := f(c)
+		yc = ev.eval(c)
+		if !finite(yc) {
+			return ev.result(c, yc, b-a, s.cycles), ErrNonFinite
+		}
 		if yc == 0 || (b-a)/2 < s.Epsilon {
-			return c
+			return ev.result(c, yc, b-a, s.cycles), nil
 		}
 		s.cycles++
-		if yc*f(a) < 0 {
+		if yc*fa < 0 {
 			b = c
 		} else {
 			a = c
+			fa = yc
 		}
 	}
-	return math.NaN()
+	return ev.result(c, yc, b-a, s.cycles), ErrMaxIterations
 }
 
-func (s *Bisection) handleInput() {
+func (s *Bisection) handleInput() error {
 	if s.CycleLimit == 0 {
 		s.CycleLimit = 100
-	} else if s.CycleLimit < 0 {
-		panic("Bisection struct value of CycleLimit should be higher than 0")
 	}
 	if s.Epsilon == 0 {
 		s.Epsilon = 0.01
 	} else if s.Epsilon < 0 {
-		panic("Bisection struct value of Epsilon should be higher than 0")
+		return fmt.Errorf("%w: Bisection struct value of Epsilon should be higher than 0", ErrInvalidConfig)
 	}
+	return nil
 }
@@ -0,0 +1,152 @@
+package equation
+
+import (
+	"fmt"
+	"math"
+)
+
+// Brent provides a method to find the zero of a function using [Brent's] method on an interval [a, b].
+// It combines the bisection method, the secant method and inverse quadratic interpolation, always keeping the zero bracketed.
+// A solution is considered definitive once the width of the bracketing interval is below Epsilon.
+//
+// Brent's method is as safe as the [Bisection] method, as it always converges, but it usually converges superlinearly on well-behaved functions.
+//
+// If 'Epsilon' is not specified, it defaults to 0.01. If 'Epsilon' is less than 0, [Brent.ZeroResult] returns [ErrInvalidConfig].
+//
+// If 'CycleLimit' is not specified, it defaults to 100.
+//
+// [Brent's]: https://en.wikipedia.org/wiki/Brent%27s_method
+type Brent struct {
+	Epsilon    float64
+	cycles     uint
+	CycleLimit uint
+}
+
+// NewBrent creates and returns a pointer to a new Brent instance with the specified values of 'epsilon' and 'cycleLimit'.
+//
+// If 'epsilon' is below 0, [Brent.ZeroResult] returns [ErrInvalidConfig].
+func NewBrent(epsilon float64, cycleLimit uint) *Brent {
+	return &Brent{Epsilon: epsilon, CycleLimit: cycleLimit}
+}
+
+func (s *Brent) Cycles() uint {
+	return s.cycles
+}
+
+// Zero finds the zero of the function 'f' using the [Brent] method on the interval [a, b].
+// At each step it tries an inverse quadratic interpolation (or a secant step when only two points are available) and falls back to bisection
+// whenever the interpolated point would leave the bracket or would not shrink it fast enough.
+// The result is returned as a float64. If no zero is found within the given constraints, it returns math.NaN().
+// Use [Brent.ZeroResult] to know why no zero was found.
+//
+// Note: The function 'f' must change sign on the interval [a, b], and it must be continuous on that interval.
+// If it does not change sign, math.NaN() is returned, and [Brent.ZeroResult] reports [ErrNoSignChange].
+func (s *Brent) Zero(f func(x float64) float64, a, b float64) float64 {
+	return rootOrNaN(s.ZeroResult(f, a, b))
+}
+
+// ZeroResult works like [Brent.Zero] but returns a [Result] describing the run together with an error.
+// The error is [ErrNoSignChange] if 'f' does not change sign on [a, b], [ErrNonFinite] if 'f' returns NaN or ±Inf,
+// [ErrMaxIterations] if 'CycleLimit' is reached and [ErrInvalidConfig] if the struct is misconfigured.
+func (s *Brent) ZeroResult(f func(x float64) float64, a, b float64) (Result, error) {
+	s.cycles = 0
+	if err := s.handleInput(); err != nil {
+		return Result{Root: math.NaN()}, err
+	}
+
+	ev := evaluator{f: f}
+	fa := ev.eval(a)
+	fb := ev.eval(b)
+	if !finite(fa) || !finite(fb) {
+		return ev.result(a, fa, b-a, s.cycles), ErrNonFinite
+	}
+	if fa*fb > 0 {
+		return ev.result(a, fa, b-a, s.cycles), ErrNoSignChange
+	}
+	if fa == 0 {
+		return ev.result(a, fa, b-a, s.cycles), nil
+	}
+	if fb == 0 {
+		return ev.result(b, fb, b-a, s.cycles), nil
+	}
+
+	c, fc := b, fb
+	var d, e float64
+	for ; s.cycles < s.CycleLimit; s.cycles++ {
+		// keep the zero bracketed between b and c
+		if (fb > 0) == (fc > 0) {
+			c, fc = a, fa
+			d = b - a
+			e = d
+		}
+		// b is always the best estimate so far
+		if math.Abs(fc) < math.Abs(fb) {
+			a, b, c = b, c, b
+			fa, fb, fc = fb, fc, fb
+		}
+
+		tol := 2*machineEpsilon*math.Abs(b) + s.Epsilon/2
+		m := (c - b) / 2
+		if math.Abs(m) <= tol || fb == 0 {
+			return ev.result(b, fb, math.Abs(c-b), s.cycles), nil
+		}
+
+		if math.Abs(e) >= tol && math.Abs(fa) > math.Abs(fb) {
+			var p, q float64
+			ratio := fb / fa
+			if a == c {
+				// secant step
+				p = 2 * m * ratio
+				q = 1 - ratio
+			} else {
+				// inverse quadratic interpolation
+				q = fa / fc
+				r := fb / fc
+				p = ratio * (2*m*q*(q-r) - (b-a)*(r-1))
+				q = (q - 1) * (r - 1) * (ratio - 1)
+			}
+			if p > 0 {
+				q = -q
+			} else {
+				p = -p
+			}
+			if 2*p < math.Min(3*m*q-math.Abs(tol*q), math.Abs(e*q)) {
+				e = d
+				d = p / q
+			} else {
+				d = m
+				e = d
+			}
+		} else {
+			d = m
+			e = d
+		}
+
+		a, fa = b, fb
+		if math.Abs(d) > tol {
+			b += d
+		} else {
+			b += math.Copysign(tol, m)
+		}
+		fb = ev.eval(b)
+		if !finite(fb) {
+			return ev.result(b, fb, math.Abs(c-b), s.cycles), ErrNonFinite
+		}
+	}
+	return ev.result(b, fb, math.Abs(c-b), s.cycles), ErrMaxIterations
+}
+
+func (s *Brent) handleInput() error {
+	if s.CycleLimit == 0 {
+		s.CycleLimit = 100
+	}
+	if s.Epsilon == 0 {
+		s.Epsilon = 0.01
+	} else if s.Epsilon < 0 {
+		return fmt.Errorf("%w: Brent struct value of Epsilon should be higher than 0", ErrInvalidConfig)
+	}
+	return nil
+}
+
+// machineEpsilon is the machine epsilon for float64, used to guard tolerances against rounding errors.
+const machineEpsilon = 2.220446049250313e-16
@@ -1,6 +1,9 @@
 package equation_test
 
 import (
+	"errors"
+	"fmt"
+	"github.com/rocas777/kairos/autodiff"
 	"github.com/rocas777/kairos/equation"
 	"math"
 	"testing"
@@ -125,3 +128,149 @@ func TestNewton(t *testing.T) {
 		})
 	}
 }
+
+func TestNewtonNegativeSide(t *testing.T) {
+	// ln is concave, so every iterate stays below the root at 1, where ln(x) < 0; a signed test would stop at the first one
+	r, err := equation.NewNewtonRaphson(1e-9, 100).ZeroResult(math.Log, func(x float64) float64 { return 1 / x }, 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(r.Root-1) > 1e-6 || math.Abs(r.Residual) > 1e-9 || r.Cycles < 2 {
+		t.Fatalf("Got: root %.12f with residual %g in %d cycles", r.Root, r.Residual, r.Cycles)
+	}
+}
+
+func TestNewtonDual(t *testing.T) {
+	tests := []struct {
+		name  string
+		f     func(x float64) float64
+		dxF   func(x float64) float64
+		fDual func(x autodiff.Dual) autodiff.Dual
+		a     float64
+	}{
+		{"smooth", smooth, dxSmooth, func(x autodiff.Dual) autodiff.Dual { return x.Mul(x).Neg().Exp().Shift(-0.3) }, 1},
+		{"oscillatory", oscillatory, dxOscillatory, autodiff.Dual.Sin, 3},
+		{"exponential", exponential, dxExponential, func(x autodiff.Dual) autodiff.Dual { return x.Exp().Shift(-4) }, 1.5},
+		{"singularity", singularity, dxSingularity, func(x autodiff.Dual) autodiff.Dual { return autodiff.Constant(1).Div(x).Shift(-0.2) }, 0.5},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			root := equation.NewNewtonRaphson(0.001, 100).ZeroDual(test.fDual, test.a)
+			check(test.f(root), t)
+			if want := equation.NewNewtonRaphson(0.001, 100).Zero(test.f, test.dxF, test.a); math.Abs(root-want) > 1e-12 {
+				t.Fatalf("Got: %.15f, wanted the same root as with the hand-written derivative: %.15f", root, want)
+			}
+		})
+	}
+}
+
+func TestBrent(t *testing.T) {
+	a := 0.0
+	b := 10.0
+
+	tests := []struct {
+		name string
+		f    func(x float64) float64
+		a    float64
+		b    float64
+	}{
+		{"smooth", smooth, a, b},
+		{"oscillatory", oscillatory, 2, 4},
+		{"exponential", exponential, a, b},
+		{"singularity", singularity, a + 1, b},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			check(test.f(equation.NewBrent(0.001, 100).Zero(test.f, test.a, test.b)), t)
+		})
+	}
+}
+
+func TestZeroResultErrors(t *testing.T) {
+	flat := func(x float64) float64 { return 1 }
+	undefined := func(x float64) float64 { return math.Log(x) }
+
+	tests := []struct {
+		name  string
+		solve func() (equation.Result, error)
+		err   error
+	}{
+		{"bisection no sign change", func() (equation.Result, error) { return equation.NewBisection(0.001, 100).ZeroResult(flat, 0, 1) }, equation.ErrNoSignChange},
+		{"bisection max iterations", func() (equation.Result, error) { return equation.NewBisection(1e-12, 5).ZeroResult(smooth, 0, 10) }, equation.ErrMaxIterations},
+		{"bisection non finite", func() (equation.Result, error) { return equation.NewBisection(0.001, 100).ZeroResult(undefined, -1, 2) }, equation.ErrNonFinite},
+		{"bisection invalid config", func() (equation.Result, error) { return equation.NewBisection(-1, 100).ZeroResult(smooth, 0, 10) }, equation.ErrInvalidConfig},
+		{"brent no sign change", func() (equation.Result, error) { return equation.NewBrent(0.001, 100).ZeroResult(flat, 0, 1) }, equation.ErrNoSignChange},
+		{"false position no sign change", func() (equation.Result, error) { return equation.NewFalsePosition(0.001, 100).ZeroResult(flat, 0, 1) }, equation.ErrNoSignChange},
+		{"secant flat", func() (equation.Result, error) { return equation.NewSecant(0.001, 100).ZeroResult(flat, 0, 1) }, equation.ErrDerivativeZero},
+		{"newton derivative zero", func() (equation.Result, error) {
+			return equation.NewNewtonRaphson(0.001, 100).ZeroResult(smooth, dxSmooth, 0)
+		}, equation.ErrDerivativeZero},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := test.solve(); !errors.Is(err, test.err) {
+				t.Fatalf("Got: %v, wanted: %v", err, test.err)
+			}
+		})
+	}
+}
+
+func TestDefaultCycleLimit(t *testing.T) {
+	// an unspecified CycleLimit allows 100 cycles, more than any of these runs needs
+	dxLog := func(x float64) float64 { return 1 / x }
+	tests := []struct {
+		name  string
+		solve func() (equation.Result, error)
+	}{
+		{"bisection", func() (equation.Result, error) { return equation.NewBisection(1e-9, 0).ZeroResult(math.Log, 0.5, 3) }},
+		{"brent", func() (equation.Result, error) { return equation.NewBrent(1e-9, 0).ZeroResult(math.Log, 0.5, 3) }},
+		{"false position", func() (equation.Result, error) {
+			return equation.NewFalsePosition(1e-9, 0).ZeroResult(math.Log, 0.5, 3)
+		}},
+		{"secant", func() (equation.Result, error) { return equation.NewSecant(1e-9, 0).ZeroResult(math.Log, 0.5, 3) }},
+		{"newton", func() (equation.Result, error) {
+			return equation.NewNewtonRaphson(1e-9, 0).ZeroResult(math.Log, dxLog, 0.5)
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r, err := test.solve()
+			if err != nil || math.Abs(r.Root-1) > 1e-6 {
+				t.Fatalf("Got: root %.12f in %d cycles, %v", r.Root, r.Cycles, err)
+			}
+		})
+	}
+}
+
+func TestEndpointRoot(t *testing.T) {
+	identity := func(x float64) float64 { return x }
+	solvers := []struct {
+		name  string
+		solve func(f func(x float64) float64, a, b float64) (equation.Result, error)
+	}{
+		{"bisection", equation.NewBisection(0.001, 100).ZeroResult},
+		{"brent", equation.NewBrent(0.001, 100).ZeroResult},
+		{"false position", equation.NewFalsePosition(0.001, 100).ZeroResult},
+	}
+	for _, solver := range solvers {
+		for _, bounds := range [][2]float64{{0, 1}, {-1, 0}} {
+			t.Run(fmt.Sprintf("%s %v", solver.name, bounds), func(t *testing.T) {
+				r, err := solver.solve(identity, bounds[0], bounds[1])
+				if err != nil || r.Root != 0 || r.Residual != 0 {
+					t.Fatalf("Got: root %g with residual %g, %v, wanted the root at 0", r.Root, r.Residual, err)
+				}
+			})
+		}
+	}
+}
+
+func TestZeroResult(t *testing.T) {
+	r, err := equation.NewBisection(0.001, 100).ZeroResult(exponential, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(r.Residual, t)
+	if r.Width > 0.002 || r.Evaluations != r.Cycles+3 {
+		t.Fatalf("Got: width %f and %d evaluations in %d cycles", r.Width, r.Evaluations, r.Cycles)
+	}
+}
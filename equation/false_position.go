@@ -1,6 +1,9 @@
 package equation
 
-import "math"
+import (
+	"fmt"
+	"math"
+)
 
 // FalsePosition provides a method to find the zero of a function using the [False Position] method on an interval [a, b].
 // The method iteratively refines the estimate of the zero based on linear interpolation.
@@ -33,21 +36,49 @@ func (s *FalsePosition) Cycles() uint {
 // Zero finds the zero of the function 'f' using the [FalsePosition] method on the interval [a, b].
 // It iteratively narrows down the interval until the solution is found within the specified precision ('Epsilon') or until the maximum number of cycles ('CycleLimit') is reached.
 // The result is returned as a float64. If no zero is found within the given constraints, it returns math.NaN().
+// Use [FalsePosition.ZeroResult] to know why no zero was found.
 //
 // Note: The function 'f' must have a zero on the interval [a, b], and it must be continuous on that interval.
 func (s *FalsePosition) Zero(f func(x float64) float64, a, b float64) float64 {
-	s.handleInput()
+	return rootOrNaN(s.ZeroResult(f, a, b))
+}
+
+// ZeroResult works like [FalsePosition.Zero] but returns a [Result] describing the run together with an error.
+// The error is [ErrNoSignChange] if 'f' does not change sign on [a, b], [ErrNonFinite] if 'f' returns NaN or ±Inf,
+// [ErrMaxIterations] if 'CycleLimit' is reached and [ErrInvalidConfig] if the struct is misconfigured.
+func (s *FalsePosition) ZeroResult(f func(x float64) float64, a, b float64) (Result, error) {
+	s.cycles = 0
+	if err := s.handleInput(); err != nil {
+		return Result{Root: math.NaN()}, err
+	}
 	side := 0
 
-	fa := f(a)
-	fb := f(b)
+	ev := evaluator{f: f}
+	fa := ev.eval(a)
+	fb := ev.eval(b)
+	if !finite(fa) || !finite(fb) {
+		return ev.result(a, fa, b-a, s.cycles), ErrNonFinite
+	}
+	if fa*fb > 0 {
+		return ev.result(a, fa, b-a, s.cycles), ErrNoSignChange
+	}
+	if fa == 0 {
+		return ev.result(a, fa, b-a, s.cycles), nil
+	}
+	if fb == 0 {
+		return ev.result(b, fb, b-a, s.cycles), nil
+	}
 
-	for s.cycles = 0; s.cycles < s.CycleLimit; s.cycles++ {
-		c := (fa*b - fb*a) / (fa - fb)
+	var c, fc float64
+	for ; s.cycles < s.CycleLimit; s.cycles++ {
+		c = (fa*b - fb*a) / (fa - fb)
+		fc = ev.eval(c)
+		if !finite(fc) {
+			return ev.result(c, fc, b-a, s.cycles), ErrNonFinite
+		}
 		if math.Abs(b-a) < s.Epsilon*math.Abs(b+a) {
-			return c
+			return ev.result(c, fc, b-a, s.cycles), nil
 		}
-		fc := f(c)
 		if fc*fb > 0 {
 			b = c
 			fb = fc
@@ -63,21 +94,20 @@ func (s *FalsePosition) Zero(f func(x float64) float64, a, b float64) float64 {
 			}
 			side = 1
 		} else {
-			return c
+			return ev.result(c, fc, b-a, s.cycles), nil
 		}
 	}
-	return math.NaN()
+	return ev.result(c, fc, b-a, s.cycles), ErrMaxIterations
 }
 
-func (s *FalsePosition) handleInput() {
+func (s *FalsePosition) handleInput() error {
 	if s.CycleLimit == 0 {
-		s.CycleLimit = 1
-	} else if s.CycleLimit < 0 {
-		panic("FalsePosition struct value of CycleLimit should be higher than 0")
+		s.CycleLimit = 100
 	}
 	if s.Epsilon == 0 {
 		s.Epsilon = 0.01
 	} else if s.Epsilon < 0 {
-		panic("FalsePosition struct value of Epsilon should be higher than 0")
+		return fmt.Errorf("%w: FalsePosition struct value of Epsilon should be higher than 0", ErrInvalidConfig)
 	}
+	return nil
 }
@@ -1,10 +1,14 @@
 package equation
 
-import "math"
+import (
+	"fmt"
+	"github.com/rocas777/kairos/autodiff"
+	"math"
+)
 
 // NewtonRaphson provides a method to find the zero of a function using the [Newton-Raphson] method.
 // The method iteratively refines the estimate of the zero based on the function's local behavior.
-// A solution is considered definitive once the change in the estimate is below Epsilon or the maximum number of cycles (CycleLimit) is reached.
+// A solution is considered definitive once |f(x)| is below Epsilon or the maximum number of cycles (CycleLimit) is reached.
 //
 // The Newton-Raphson method is generally faster than the bisection method but requires the function to be differentiable.
 //
@@ -32,30 +36,70 @@ func (s *NewtonRaphson) Cycles() uint {
 
 // Zero finds the zero of the function 'f' using the [Newton-Raphson] method.
 // It iteratively refines the estimate of the zero based on the function's local behavior using the derivative function 'dxF'.
-// A solution is considered definitive once the change in the estimate is below 'Epsilon' or the maximum number of cycles ('CycleLimit') is reached.
+// A solution is considered definitive once |f(x)| is below 'Epsilon' or the maximum number of cycles ('CycleLimit') is reached.
 // The initial estimate is provided by the parameter 'a'.
 // If no zero is found within the given constraints, it returns math.NaN().
+// Use [NewtonRaphson.ZeroResult] to know why no zero was found.
 func (s *NewtonRaphson) Zero(f func(x float64) float64, dxF func(x float64) float64, a float64) float64 {
-	s.handleInput()
+	return rootOrNaN(s.ZeroResult(f, dxF, a))
+}
+
+// ZeroResult works like [NewtonRaphson.Zero] but returns a [Result] describing the run together with an error.
+// The error is [ErrDerivativeZero] if 'dxF' vanishes, [ErrNonFinite] if 'f' or 'dxF' return NaN or ±Inf,
+// [ErrMaxIterations] if 'CycleLimit' is reached and [ErrInvalidConfig] if the struct is misconfigured.
+func (s *NewtonRaphson) ZeroResult(f func(x float64) float64, dxF func(x float64) float64, a float64) (Result, error) {
+	s.cycles = 0
+	if err := s.handleInput(); err != nil {
+		return Result{Root: math.NaN()}, err
+	}
+	ev := evaluator{f: f}
 	x := a
-	for s.cycles = 0; s.cycles < s.CycleLimit; s.cycles++ {
-		if f(x) < s.Epsilon {
-			return x
+	step := 0.0
+	var fx float64
+	for ; s.cycles < s.CycleLimit; s.cycles++ {
+		fx = ev.eval(x)
+		if !finite(fx) {
+			return ev.result(x, fx, step, s.cycles), ErrNonFinite
+		}
+		if math.Abs(fx) < s.Epsilon {
+			return ev.result(x, fx, step, s.cycles), nil
+		}
+		d := dxF(x)
+		ev.count++
+		if !finite(d) {
+			return ev.result(x, fx, step, s.cycles), ErrNonFinite
 		}
-		x = x - f(x)/dxF(x)
+		if d == 0 {
+			return ev.result(x, fx, step, s.cycles), ErrDerivativeZero
+		}
+		step = fx / d
+		x = x - step
 	}
-	return math.NaN()
+	fx = ev.eval(x)
+	return ev.result(x, fx, step, s.cycles), ErrMaxIterations
+}
+
+// ZeroDual works like [NewtonRaphson.Zero], but 'f' is written against [autodiff.Dual] numbers,
+// so its exact derivative is obtained by automatic differentiation instead of a hand-written 'dxF'.
+func (s *NewtonRaphson) ZeroDual(f func(x autodiff.Dual) autodiff.Dual, a float64) float64 {
+	return rootOrNaN(s.ZeroDualResult(f, a))
+}
+
+// ZeroDualResult works like [NewtonRaphson.ZeroResult], but 'f' is written against [autodiff.Dual] numbers,
+// so its exact derivative is obtained by automatic differentiation instead of a hand-written 'dxF'.
+func (s *NewtonRaphson) ZeroDualResult(f func(x autodiff.Dual) autodiff.Dual, a float64) (Result, error) {
+	fx, dxF := autodiff.Split(f)
+	return s.ZeroResult(fx, dxF, a)
 }
 
-func (s *NewtonRaphson) handleInput() {
+func (s *NewtonRaphson) handleInput() error {
 	if s.CycleLimit == 0 {
-		s.CycleLimit = 1
-	} else if s.CycleLimit < 0 {
-		panic("NewtonRaphson struct value of CycleLimit should be higher than 0")
+		s.CycleLimit = 100
 	}
 	if s.Epsilon == 0 {
 		s.Epsilon = 0.01
 	} else if s.Epsilon < 0 {
-		panic("NewtonRaphson struct value of Epsilon should be higher than 0")
+		return fmt.Errorf("%w: NewtonRaphson struct value of Epsilon should be higher than 0", ErrInvalidConfig)
 	}
+	return nil
 }
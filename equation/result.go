@@ -0,0 +1,63 @@
+package equation
+
+import (
+	"errors"
+	"math"
+)
+
+var (
+	// ErrNoSignChange is returned by bracketing methods when 'f' has the same sign on both ends of the interval [a, b].
+	ErrNoSignChange = errors.New("equation: function does not change sign on the interval")
+	// ErrMaxIterations is returned when 'CycleLimit' is reached before the 'Epsilon' criterion is met.
+	ErrMaxIterations = errors.New("equation: cycle limit reached before convergence")
+	// ErrDerivativeZero is returned when the derivative (or the secant slope) vanishes and no further step can be taken.
+	ErrDerivativeZero = errors.New("equation: derivative is zero")
+	// ErrNonFinite is returned when the function, or its derivative, evaluates to NaN or ±Inf.
+	ErrNonFinite = errors.New("equation: function returned a non-finite value")
+	// ErrInvalidConfig is returned when a method is configured with invalid values, such as a negative 'Epsilon'.
+	ErrInvalidConfig = errors.New("equation: invalid configuration")
+)
+
+// Result holds the outcome of a root-finding run.
+//
+// 'Root' is the last estimate of the zero, even when an error is returned alongside it, and 'Residual' is the value of 'f' at 'Root'.
+// 'Width' is the width of the bracketing interval for bracketing methods, and the size of the last step for open methods.
+// 'Cycles' is the number of cycles performed and 'Evaluations' is the number of calls made to 'f' (and to 'dxF' for [NewtonRaphson]).
+type Result struct {
+	Root        float64
+	Residual    float64
+	Width       float64
+	Cycles      uint
+	Evaluations uint
+}
+
+// evaluator wraps a function and counts how many times it was called.
+type evaluator struct {
+	f     func(x float64) float64
+	count uint
+}
+
+func (e *evaluator) eval(x float64) float64 {
+	e.count++
+	return e.f(x)
+}
+
+func (e *evaluator) result(root, residual, width float64, cycles uint) Result {
+	return Result{Root: root, Residual: residual, Width: width, Cycles: cycles, Evaluations: e.count}
+}
+
+func finite(y float64) bool {
+	return !math.IsNaN(y) && !math.IsInf(y, 0)
+}
+
+// rootOrNaN adapts the output of a ZeroResult method to the float64 Zero methods.
+// Invalid configurations keep raising a panic, every other error is reported as math.NaN().
+func rootOrNaN(r Result, err error) float64 {
+	if errors.Is(err, ErrInvalidConfig) {
+		panic(err.Error())
+	}
+	if err != nil {
+		return math.NaN()
+	}
+	return r.Root
+}
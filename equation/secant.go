@@ -1,6 +1,9 @@
 package equation
 
-import "math"
+import (
+	"fmt"
+	"math"
+)
 
 // Secant provides a method to find the zero of a function using the [Secant] method.
 // The method iteratively refines the estimate of the zero based on a secant line between two points.
@@ -34,32 +37,52 @@ func (s *Secant) Cycles() uint {
 // It iteratively refines the estimate of the zero based on a secant line between two points.
 // A solution is considered definitive once the change in the estimate is below 'Epsilon' or the maximum number of cycles ('CycleLimit') is reached.
 // If no zero is found within the given constraints, it returns math.NaN().
+// Use [Secant.ZeroResult] to know why no zero was found.
 func (s *Secant) Zero(f func(x float64) float64, a, b float64) float64 {
-	s.handleInput()
-	x2 := 20.0
-	x0 := a
-	x1 := b
-	for s.cycles = 0; s.cycles < s.CycleLimit; s.cycles++ {
-		x2 = (x0*f(x1) - x1*f(x0)) / (f(x1) - f(x0))
-		x0 = x1
-		x1 = x2
-		g := f(x2)
-		if math.Abs(g) < s.Epsilon {
-			return x2
+	return rootOrNaN(s.ZeroResult(f, a, b))
+}
+
+// ZeroResult works like [Secant.Zero] but returns a [Result] describing the run together with an error.
+// The error is [ErrDerivativeZero] if the secant line becomes horizontal, [ErrNonFinite] if 'f' returns NaN or ±Inf,
+// [ErrMaxIterations] if 'CycleLimit' is reached and [ErrInvalidConfig] if the struct is misconfigured.
+func (s *Secant) ZeroResult(f func(x float64) float64, a, b float64) (Result, error) {
+	s.cycles = 0
+	if err := s.handleInput(); err != nil {
+		return Result{Root: math.NaN()}, err
+	}
+	ev := evaluator{f: f}
+	x0, x1 := a, b
+	f0 := ev.eval(x0)
+	f1 := ev.eval(x1)
+	if !finite(f0) || !finite(f1) {
+		return ev.result(x1, f1, x1-x0, s.cycles), ErrNonFinite
+	}
+	for ; s.cycles < s.CycleLimit; s.cycles++ {
+		if f1 == f0 {
+			return ev.result(x1, f1, x1-x0, s.cycles), ErrDerivativeZero
+		}
+		x2 := (x0*f1 - x1*f0) / (f1 - f0)
+		f2 := ev.eval(x2)
+		x0, f0 = x1, f1
+		x1, f1 = x2, f2
+		if !finite(f2) {
+			return ev.result(x1, f1, x1-x0, s.cycles), ErrNonFinite
+		}
+		if math.Abs(f2) < s.Epsilon {
+			return ev.result(x1, f1, x1-x0, s.cycles), nil
 		}
 	}
-	return math.NaN()
+	return ev.result(x1, f1, x1-x0, s.cycles), ErrMaxIterations
 }
 
-func (s *Secant) handleInput() {
+func (s *Secant) handleInput() error {
 	if s.CycleLimit == 0 {
-		s.CycleLimit = 1
-	} else if s.CycleLimit < 0 {
-		panic("Secant struct value of CycleLimit should be higher than 0")
+		s.CycleLimit = 100
 	}
 	if s.Epsilon == 0 {
 		s.Epsilon = 0.01
 	} else if s.Epsilon < 0 {
-		panic("Secant struct value of Epsilon should be higher than 0")
+		return fmt.Errorf("%w: Secant struct value of Epsilon should be higher than 0", ErrInvalidConfig)
 	}
+	return nil
 }
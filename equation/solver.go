@@ -0,0 +1,26 @@
+package equation
+
+// Solver is implemented by the methods that find the zero of a function starting from two points 'a' and 'b'.
+// For the bracketing methods ([Bisection], [Brent] and [FalsePosition]) 'a' and 'b' delimit an interval where 'f' changes sign,
+// while for open methods such as [Secant] they are the two initial estimates.
+type Solver interface {
+	Zero(f func(x float64) float64, a, b float64) float64
+	ZeroResult(f func(x float64) float64, a, b float64) (Result, error)
+	Cycles() uint
+}
+
+// DerivativeSolver is implemented by the methods that find the zero of a function from a single initial estimate 'a',
+// using the derivative function 'dxF', such as [NewtonRaphson].
+type DerivativeSolver interface {
+	Zero(f func(x float64) float64, dxF func(x float64) float64, a float64) float64
+	ZeroResult(f func(x float64) float64, dxF func(x float64) float64, a float64) (Result, error)
+	Cycles() uint
+}
+
+var (
+	_ Solver           = (*Bisection)(nil)
+	_ Solver           = (*Brent)(nil)
+	_ Solver           = (*FalsePosition)(nil)
+	_ Solver           = (*Secant)(nil)
+	_ DerivativeSolver = (*NewtonRaphson)(nil)
+)
@@ -0,0 +1,210 @@
+package integration
+
+import (
+	"github.com/rocas777/kairos"
+	"github.com/rocas777/kairos/internal/fourier"
+	"math"
+)
+
+// ClenshawCurtis provides a method to calculate the definite integral of a given function using [Clenshaw-Curtis] quadrature.
+// The function is interpolated by a Chebyshev polynomial of degree N at the Chebyshev extreme points cos(jπ/N), whose integral is then
+// computed exactly from its Chebyshev coefficients. For smooth functions it converges about as fast as Gauss-Legendre quadrature.
+// The coefficients take O(N log N) operations when N is a power of 2, like its default, through a fast Fourier transform,
+// and O(N²) otherwise.
+//
+// The extreme points of order N are a subset of those of order 2N, so doubling the order reuses every previous evaluation.
+// If 'Epsilon' is specified, the order is doubled from N until two successive estimates differ by less than 'Epsilon' or the order would exceed 'MaxN'.
+// Otherwise the rule of order N is applied once.
+//
+// The Chebyshev coefficients of the last interpolant are available through [ClenshawCurtis.Coefficients].
+//
+// If N is not specified, it defaults to 16. If 'Epsilon' is less than 0, a panic is raised.
+//
+// If 'MaxN' is not specified, it defaults to 4096.
+//
+// [Clenshaw-Curtis]: https://en.wikipedia.org/wiki/Clenshaw%E2%80%93Curtis_quadrature
+type ClenshawCurtis struct {
+	N            uint
+	Epsilon      float64
+	MaxN         uint
+	cycles       uint
+	coefficients []float64
+}
+
+// NewClenshawCurtis creates and returns a pointer to a new [ClenshawCurtis] instance that applies the rule of order 'n' once.
+func NewClenshawCurtis(n uint) *ClenshawCurtis {
+	return &ClenshawCurtis{N: n}
+}
+
+// NewAdaptiveClenshawCurtis creates and returns a pointer to a new [ClenshawCurtis] instance that doubles the order until
+// two successive estimates differ by less than 'epsilon'.
+//
+// If 'epsilon' is below 0, a panic is raised.
+func NewAdaptiveClenshawCurtis(epsilon float64) *ClenshawCurtis {
+	return &ClenshawCurtis{Epsilon: epsilon}
+}
+
+// Cycles returns the number of evaluations of the integrand made by the last integration.
+func (c *ClenshawCurtis) Cycles() uint {
+	return c.cycles
+}
+
+// Coefficients returns a copy of the Chebyshev coefficients of the interpolant built by the last integration.
+// The interpolant over [a, b] is the sum of coefficients[k]·T_k(t) for k from 0 to its order, where t = (2x-a-b)/(b-a)
+// and T_k is the Chebyshev polynomial of the first kind of degree k.
+func (c *ClenshawCurtis) Coefficients() []float64 {
+	return append([]float64(nil), c.coefficients...)
+}
+
+// DefiniteIntegral calculates the definite integral of the given function 'f' using Clenshaw-Curtis quadrature.
+//
+// The function 'f' represents the integrand, and 'a' and 'b' define the integration interval.
+// The result is returned as a float64 representing the calculated definite integral.
+func (c *ClenshawCurtis) DefiniteIntegral(f func(x float64) float64, a, b float64) float64 {
+	r, _ := c.DefiniteIntegralResult(f, a, b)
+	return r.Value
+}
+
+// DefiniteIntegralResult works like [ClenshawCurtis.DefiniteIntegral] but returns a [Result] holding the integral, the difference with
+// the estimate of half the order as its estimated error and the number of evaluations. The estimate of half the order comes for free from
+// the nested points; when the order is odd, the error is reported as NaN. The error is [ErrMaxEvaluations] if 'Epsilon' is specified and
+// 'MaxN' is reached before the estimates agree, and [ErrNonFinite] if 'f' returns NaN or ±Inf.
+func (c *ClenshawCurtis) DefiniteIntegralResult(f func(x float64) float64, a, b float64) (Result, error) {
+	c.handleInput()
+
+	c.cycles = 0
+	center, half := (a+b)/2, (b-a)/2
+	n := c.N
+	values := make([]float64, n+1)
+	for j := range values {
+		c.cycles++
+		values[j] = f(center + half*math.Cos(math.Pi*float64(j)/float64(n)))
+	}
+
+	// the estimate of half the order, which is NaN when the order is odd
+	previous := math.NaN()
+	if n%2 == 0 {
+		coarse := make([]float64, n/2+1)
+		for j := range coarse {
+			coarse[j] = values[2*j]
+		}
+		previous, _ = clenshawCurtisRule(coarse)
+		previous *= half
+	}
+
+	for {
+		var estimate float64
+		estimate, c.coefficients = clenshawCurtisRule(values)
+		estimate *= half
+		if !finite(estimate) {
+			return Result{Value: estimate, ErrorEstimate: math.Inf(1), Evaluations: c.cycles}, ErrNonFinite
+		}
+		err := math.Abs(estimate - previous)
+		if c.Epsilon == 0 || err < c.Epsilon {
+			return Result{Value: estimate, ErrorEstimate: err, Evaluations: c.cycles}, nil
+		}
+		if 2*n > c.MaxN {
+			return Result{Value: estimate, ErrorEstimate: err, Evaluations: c.cycles}, ErrMaxEvaluations
+		}
+
+		// the points of order n are the even points of order 2n
+		previous = estimate
+		n *= 2
+		next := make([]float64, n+1)
+		for j := range next {
+			if j%2 == 0 {
+				next[j] = values[j/2]
+				continue
+			}
+			c.cycles++
+			next[j] = f(center + half*math.Cos(math.Pi*float64(j)/float64(n)))
+		}
+		values = next
+	}
+}
+
+// AntiDerivative calculates the approximate antiderivative of the given function 'f' using Clenshaw-Curtis quadrature.
+// It samples the antiderivative at 'samples' points within the interval [a, b].
+// The antiderivative is the integral from 0 to each sampled point 'x'. It integrates from 0 to 'a' once, then only the panel between
+// each sample and the previous one, with its share of 'Epsilon', so the cost grows linearly with the number of samples.
+// [ClenshawCurtis.Cycles] then reports the total number of evaluations of 'f'.
+//
+// If 'samples' is less than 2, it defaults to 2.
+//
+// The function 'f' represents the integrand, and 'a' and 'b' define the integration interval.
+// The result is returned as a slice of [kairos.Pair] representing the sampled points and their corresponding antiderivative values.
+func (c *ClenshawCurtis) AntiDerivative(f func(x float64) float64, a, b float64, samples uint) []kairos.Pair {
+	c.handleInput()
+	if samples < 2 {
+		samples = 2
+	}
+	panel := &ClenshawCurtis{N: c.N, Epsilon: c.Epsilon * panelShare(samples), MaxN: c.MaxN}
+	out, evaluations := antiDerivative(c.DefiniteIntegral, panel.DefiniteIntegral, f, a, b, samples)
+	c.cycles = evaluations
+	return out
+}
+
+func (c *ClenshawCurtis) handleInput() {
+	if c.N == 0 {
+		c.N = 16
+	}
+	if c.Epsilon < 0 {
+		panic("ClenshawCurtis struct value of Epsilon should be higher than 0")
+	}
+	if c.MaxN == 0 {
+		c.MaxN = 4096
+	}
+}
+
+// clenshawCurtisRule returns the integral over [-1, 1] of the Chebyshev interpolant through 'values', the function values at the
+// extreme points cos(jπ/n), along with its Chebyshev coefficients.
+func clenshawCurtisRule(values []float64) (float64, []float64) {
+	n := len(values) - 1
+	if n == 0 {
+		// a single point at t = 1 only defines a constant
+		return 2 * values[0], []float64{values[0]}
+	}
+	coefficients := chebyshevCoefficients(values)
+	out := 0.0
+	for k := 0; k <= n; k += 2 {
+		out += coefficients[k] * 2 / float64(1-k*k)
+	}
+	return out, coefficients
+}
+
+// chebyshevCoefficients returns the Chebyshev coefficients of the interpolant through 'values' at the extreme points cos(jπ/n), n > 0,
+// which are given by a type-I discrete cosine transform. When n is a power of 2, the transform takes O(n log n) operations through
+// a fast Fourier transform of the even extension of 'values'; otherwise it is computed directly in O(n²).
+func chebyshevCoefficients(values []float64) []float64 {
+	n := len(values) - 1
+	coefficients := make([]float64, n+1)
+	if n&(n-1) == 0 {
+		// the even extension v[0], ..., v[n], v[n-1], ..., v[1] turns the cosine transform into a Fourier transform of length 2n
+		extension := make([]complex128, 2*n)
+		for j, v := range values {
+			extension[j] = complex(v, 0)
+			if j > 0 && j < n {
+				extension[2*n-j] = complex(v, 0)
+			}
+		}
+		fourier.FFT(extension)
+		for k := range coefficients {
+			coefficients[k] = real(extension[k]) / float64(n)
+		}
+	} else {
+		cos := make([]float64, 2*n)
+		for m := range cos {
+			cos[m] = math.Cos(math.Pi * float64(m) / float64(n))
+		}
+		for k := range coefficients {
+			sum := (values[0] + values[n]*cos[(n*k)%(2*n)]) / 2
+			for j := 1; j < n; j++ {
+				sum += values[j] * cos[(j*k)%(2*n)]
+			}
+			coefficients[k] = 2 * sum / float64(n)
+		}
+	}
+	coefficients[0] /= 2
+	coefficients[n] /= 2
+	return coefficients
+}
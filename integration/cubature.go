@@ -0,0 +1,146 @@
+package integration
+
+// Cubature is implemented by the methods of this package that integrate a function of several variables
+// over an axis-aligned box, whose lower and upper corners are given by 'a' and 'b'.
+// The slice passed to 'f' is reused between calls, so it must not be retained or modified.
+type Cubature interface {
+	DefiniteIntegral(f func(x []float64) float64, a, b []float64) float64
+	Cycles() uint
+}
+
+var (
+	_ Cubature = (*TrapezoidCubature)(nil)
+	_ Cubature = (*Simpson_1_3Cubature)(nil)
+	_ Cubature = (*GenzMalik)(nil)
+)
+
+// TrapezoidCubature provides a method to calculate the definite integral of a function of several variables over a box,
+// using the tensor product of the [Trapezoid] rule along every axis.
+// Each axis is divided into N pieces, so the function is evaluated on a grid of (N+1)^d points, where d is the number of dimensions.
+//
+// If N is not specified, it defaults to 10.
+type TrapezoidCubature struct {
+	N      uint
+	cycles uint
+}
+
+// NewTrapezoidCubature creates and returns a pointer to a new [TrapezoidCubature] instance with the specified value of 'n'.
+func NewTrapezoidCubature(n uint) *TrapezoidCubature {
+	return &TrapezoidCubature{N: n}
+}
+
+func (t *TrapezoidCubature) Cycles() uint {
+	return t.cycles
+}
+
+// DefiniteIntegral calculates the definite integral of the given function 'f' over the box [a, b] using the tensor product of the Trapezoidal Rule.
+//
+// The function 'f' represents the integrand, and 'a' and 'b' hold the lower and upper bounds of each dimension.
+// The result is returned as a float64 representing the calculated definite integral.
+func (t *TrapezoidCubature) DefiniteIntegral(f func(x []float64) float64, a, b []float64) float64 {
+	t.handleInput()
+	weights := make([]float64, t.N+1)
+	for i := range weights {
+		weights[i] = 1
+	}
+	weights[0], weights[t.N] = 0.5, 0.5
+	return tensorProduct(f, a, b, weights, 1/float64(t.N), &t.cycles)
+}
+
+func (t *TrapezoidCubature) handleInput() {
+	if t.N == 0 {
+		t.N = 10
+	}
+}
+
+// Simpson_1_3Cubature provides a method to calculate the definite integral of a function of several variables over a box,
+// using the tensor product of the [Simpson_1_3] rule along every axis.
+// Each axis is divided into N pieces, so the function is evaluated on a grid of (N+1)^d points, where d is the number of dimensions.
+//
+// If N is not specified, it defaults to 2. If the value is odd, a panic will be raised.
+type Simpson_1_3Cubature struct {
+	N      uint
+	cycles uint
+}
+
+// NewSimpson_1_3Cubature creates and returns a pointer to a new [Simpson_1_3Cubature] instance with the specified value of 'n'.
+// As with [NewSimpson_1_3], each axis is divided into 2n pieces.
+func NewSimpson_1_3Cubature(n uint) *Simpson_1_3Cubature {
+	return &Simpson_1_3Cubature{N: n * 2}
+}
+
+func (s *Simpson_1_3Cubature) Cycles() uint {
+	return s.cycles
+}
+
+// DefiniteIntegral calculates the definite integral of the given function 'f' over the box [a, b] using the tensor product of the Simpson 1/3 rule.
+//
+// The function 'f' represents the integrand, and 'a' and 'b' hold the lower and upper bounds of each dimension.
+// The result is returned as a float64 representing the calculated definite integral.
+func (s *Simpson_1_3Cubature) DefiniteIntegral(f func(x []float64) float64, a, b []float64) float64 {
+	s.handleInput()
+	weights := make([]float64, s.N+1)
+	for i := range weights {
+		weights[i] = float64(2 + 2*(i%2))
+	}
+	weights[0], weights[s.N] = 1, 1
+	return tensorProduct(f, a, b, weights, 1/float64(3*s.N), &s.cycles)
+}
+
+func (s *Simpson_1_3Cubature) handleInput() {
+	if s.N == 0 {
+		s.N = 2
+	} else if s.N%2 != 0 {
+		panic("Simpson_1_3Cubature struct value of N should be even")
+	}
+}
+
+// tensorProduct integrates 'f' over the box [a, b] with the tensor product of a one dimensional rule on equally spaced nodes.
+// 'weights' holds the weights of the rule on its len(weights) nodes, and 'scale' multiplies them so that they apply to a unit interval.
+// Every evaluation of 'f' is counted in 'cycles'.
+func tensorProduct(f func(x []float64) float64, a, b []float64, weights []float64, scale float64, cycles *uint) float64 {
+	checkBox(a, b)
+	*cycles = 0
+	d := len(a)
+	n := len(weights) - 1
+	volume := 1.0
+	for i := range a {
+		volume *= (b[i] - a[i]) * scale
+	}
+
+	index := make([]int, d)
+	x := make([]float64, d)
+	out := 0.0
+	for {
+		w := 1.0
+		for i := range index {
+			x[i] = a[i] + (b[i]-a[i])*float64(index[i])/float64(n)
+			w *= weights[index[i]]
+		}
+		*cycles++
+		out += w * f(x)
+
+		// advance the grid index like an odometer
+		i := 0
+		for ; i < d; i++ {
+			index[i]++
+			if index[i] <= n {
+				break
+			}
+			index[i] = 0
+		}
+		if i == d {
+			break
+		}
+	}
+	return out * volume
+}
+
+func checkBox(a, b []float64) {
+	if len(a) != len(b) {
+		panic("the lower and upper bounds of the box should have the same dimension")
+	}
+	if len(a) == 0 {
+		panic("the box should have at least one dimension")
+	}
+}
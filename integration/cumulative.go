@@ -0,0 +1,166 @@
+package integration
+
+import "github.com/rocas777/kairos"
+
+// cumulativeNewtonCotes integrates 'f' panel by panel between 'samples' equally spaced points of [a, b], starting from 'start'.
+// Each panel is split into 'pieces' subintervals, integrated with the closed Newton-Cotes rule given by 'weights' and 'scale',
+// and every point of the grid is evaluated exactly once. It returns the running integral and the number of evaluations.
+func cumulativeNewtonCotes(f func(x float64) float64, a, b float64, samples, pieces uint, weights []float64, scale, start float64) ([]kairos.Pair, uint) {
+	order := uint(len(weights) - 1)
+	out := make([]kairos.Pair, samples)
+	sampleH := (b - a) / float64(samples-1)
+	h := sampleH / float64(pieces)
+
+	values := make([]float64, pieces+1)
+	values[0] = f(a)
+	evaluations := uint(1)
+	y := start
+	out[0] = kairos.Pair{X: a, Y: y}
+	for i := 1; i < int(samples); i++ {
+		x0 := a + float64(i-1)*sampleH
+		for j := 1; j <= int(pieces); j++ {
+			values[j] = f(x0 + float64(j)*h)
+			evaluations++
+		}
+		partialOut := 0.0
+		for k := uint(0); k < pieces; k += order {
+			for w, weight := range weights {
+				partialOut += weight * values[k+uint(w)]
+			}
+		}
+		y += partialOut * h * scale
+		out[i] = kairos.Pair{X: a + float64(i)*sampleH, Y: y}
+		values[0] = values[pieces]
+	}
+	return out, evaluations
+}
+
+// piecesPerPanel spreads 'n' subintervals over the 'samples'-1 panels, rounding up to a multiple of the rule 'order'.
+func piecesPerPanel(n, samples, order uint) uint {
+	pieces := (n + samples - 2) / (samples - 1)
+	pieces = (pieces + order - 1) / order * order
+	if pieces < order {
+		pieces = order
+	}
+	return pieces
+}
+
+// cumulativeStart returns 'constant' plus the integral of 'f' from 'lower' to 'a', computed with 'definiteIntegral',
+// along with the number of evaluations it took.
+func cumulativeStart(definiteIntegral func(f func(x float64) float64, a, b float64) float64, f func(x float64) float64, a, lower, constant float64) (float64, uint) {
+	if lower == a {
+		return constant, 0
+	}
+	evaluations := uint(0)
+	counted := func(x float64) float64 {
+		evaluations++
+		return f(x)
+	}
+	return constant + definiteIntegral(counted, lower, a), evaluations
+}
+
+// cumulativePanels integrates 'f' panel by panel between 'samples' equally spaced points of [a, b], starting from 'start'.
+// Each panel is integrated once with 'panelIntegral' and added to the previous partial sum, so the cost grows linearly with the number of samples.
+// It returns the running integral and the number of evaluations.
+func cumulativePanels(panelIntegral func(f func(x float64) float64, a, b float64) float64, f func(x float64) float64, a, b float64, samples uint, start float64) ([]kairos.Pair, uint) {
+	evaluations := uint(0)
+	counted := func(x float64) float64 {
+		evaluations++
+		return f(x)
+	}
+
+	out := make([]kairos.Pair, samples)
+	sampleH := (b - a) / float64(samples-1)
+	y := start
+	out[0] = kairos.Pair{X: a, Y: y}
+	for i := 1; i < int(samples); i++ {
+		x0 := a + float64(i-1)*sampleH
+		x1 := a + float64(i)*sampleH
+		y += panelIntegral(counted, x0, x1)
+		out[i] = kairos.Pair{X: x1, Y: y}
+	}
+	return out, evaluations
+}
+
+// antiDerivative samples F(x) = ∫ f from 0 to x at 'samples' points within [a, b]. It integrates from 0 to 'a' once with 'definiteIntegral',
+// then each panel between consecutive samples with 'panelIntegral'. It returns the samples and the total number of evaluations.
+func antiDerivative(definiteIntegral, panelIntegral func(f func(x float64) float64, a, b float64) float64, f func(x float64) float64, a, b float64, samples uint) ([]kairos.Pair, uint) {
+	start, evaluations := cumulativeStart(definiteIntegral, f, a, 0, 0)
+	out, panelEvaluations := cumulativePanels(panelIntegral, f, a, b, samples, start)
+	return out, evaluations + panelEvaluations
+}
+
+// panelShare returns the fraction of the interval covered by one of the panels between 'samples' points. The absolute tolerances
+// of the panels are scaled by it, so that their errors add up to about the tolerance over the whole interval.
+func panelShare(samples uint) float64 {
+	return 1 / float64(samples-1)
+}
+
+// CumulativeIntegral calculates the running integral F(x) = constant + ∫ f from 'lower' to x, sampled at 'samples' points within [a, b].
+// Instead of integrating from scratch for every sample, it integrates panel by panel from 'a', reusing the previous partial sum and the
+// function values shared by neighbouring panels. The N subintervals are spread over the whole interval [a, b], with at least one per panel,
+// so the cost grows linearly with the number of samples. [Trapezoid.Cycles] then reports the total number of evaluations of 'f'.
+//
+// If 'samples' is less than 2, it defaults to 2.
+func (t *Trapezoid) CumulativeIntegral(f func(x float64) float64, a, b float64, samples uint, lower, constant float64) []kairos.Pair {
+	t.handleInput()
+	if samples < 2 {
+		samples = 2
+	}
+	start, evaluations := cumulativeStart(t.DefiniteIntegral, f, a, lower, constant)
+	out, panelEvaluations := cumulativeNewtonCotes(f, a, b, samples, piecesPerPanel(t.N, samples, 1), []float64{1, 1}, 1.0/2, start)
+	t.cycles = evaluations + panelEvaluations
+	return out
+}
+
+// CumulativeIntegral calculates the running integral F(x) = constant + ∫ f from 'lower' to x, sampled at 'samples' points within [a, b].
+// Instead of integrating from scratch for every sample, it integrates panel by panel from 'a', reusing the previous partial sum and the
+// function values shared by neighbouring panels. The N subintervals are spread over the whole interval [a, b], with at least two per panel,
+// so the cost grows linearly with the number of samples. [Simpson_1_3.Cycles] then reports the total number of evaluations of 'f'.
+//
+// If 'samples' is less than 2, it defaults to 2.
+func (s *Simpson_1_3) CumulativeIntegral(f func(x float64) float64, a, b float64, samples uint, lower, constant float64) []kairos.Pair {
+	s.handleInput()
+	if samples < 2 {
+		samples = 2
+	}
+	start, evaluations := cumulativeStart(s.DefiniteIntegral, f, a, lower, constant)
+	out, panelEvaluations := cumulativeNewtonCotes(f, a, b, samples, piecesPerPanel(s.N, samples, 2), []float64{1, 4, 1}, 1.0/3, start)
+	s.cycles = evaluations + panelEvaluations
+	return out
+}
+
+// CumulativeIntegral calculates the running integral F(x) = constant + ∫ f from 'lower' to x, sampled at 'samples' points within [a, b].
+// Instead of integrating from scratch for every sample, it integrates panel by panel from 'a', reusing the previous partial sum and the
+// function values shared by neighbouring panels. The N subintervals are spread over the whole interval [a, b], with at least three per panel,
+// so the cost grows linearly with the number of samples. [Simpson_3_8.Cycles] then reports the total number of evaluations of 'f'.
+//
+// If 'samples' is less than 2, it defaults to 2.
+func (s *Simpson_3_8) CumulativeIntegral(f func(x float64) float64, a, b float64, samples uint, lower, constant float64) []kairos.Pair {
+	s.handleInput()
+	if samples < 2 {
+		samples = 2
+	}
+	start, evaluations := cumulativeStart(s.DefiniteIntegral, f, a, lower, constant)
+	out, panelEvaluations := cumulativeNewtonCotes(f, a, b, samples, piecesPerPanel(s.N, samples, 3), []float64{1, 3, 3, 1}, 3.0/8, start)
+	s.cycles = evaluations + panelEvaluations
+	return out
+}
+
+// CumulativeIntegral calculates the running integral F(x) = constant + ∫ f from 'lower' to x, sampled at 'samples' points within [a, b].
+// Instead of integrating from scratch for every sample, it integrates panel by panel from 'a', reusing the previous partial sum.
+// Each panel is integrated adaptively with a share of 'Epsilon' proportional to its width, so the cost grows linearly with the number of samples.
+// [SimpsonAdaptive.Cycles] then reports the total number of evaluations of 'f'.
+//
+// If 'samples' is less than 2, it defaults to 2.
+func (s *SimpsonAdaptive) CumulativeIntegral(f func(x float64) float64, a, b float64, samples uint, lower, constant float64) []kairos.Pair {
+	s.handleInput()
+	if samples < 2 {
+		samples = 2
+	}
+	start, evaluations := cumulativeStart(s.DefiniteIntegral, f, a, lower, constant)
+	panel := &SimpsonAdaptive{Epsilon: s.Epsilon * panelShare(samples)}
+	out, panelEvaluations := cumulativePanels(panel.DefiniteIntegral, f, a, b, samples, start)
+	s.cycles = evaluations + panelEvaluations
+	return out
+}
@@ -0,0 +1,39 @@
+// Package integration provides utilities for numerical integration of functions.
+// Users can choose the appropriate method based on the precision and efficiency requirements
+// of their mathematical analysis.
+//
+// Definite integrals of functions of a single variable:
+//   - [Trapezoid]
+//   - [Simpson_1_3]
+//   - [Simpson_3_8]
+//   - [SimpsonAdaptive]
+//   - [Romberg]
+//   - [GaussLegendre]
+//   - [GaussKronrod]
+//   - [ClenshawCurtis]
+//   - [TanhSinh]
+//   - [Improper]
+//   - [Filon]
+//   - [Levin]
+//   - [MonteCarlo]
+//
+// Integrals against a weight or around a pole:
+//   - [GaussHermite]
+//   - [GaussLaguerre]
+//   - [GaussJacobi]
+//   - [GaussChebyshev]
+//   - [PrincipalValue]
+//
+// Integrals of functions of several variables over boxes:
+//   - [TrapezoidCubature]
+//   - [Simpson_1_3Cubature]
+//   - [GenzMalik]
+//   - [MonteCarlo]
+//
+// Sampled data, given as [kairos.Pair] slices:
+//   - [SampledIntegral]
+//   - [CumulativeSampledIntegral]
+//
+// Every method of a single variable implements the [Integrator] interface, except for the weighted Gaussian rules and [PrincipalValue],
+// and every cubature method implements the [Cubature] interface.
+package integration
@@ -0,0 +1,322 @@
+package integration
+
+import (
+	"container/heap"
+	"github.com/rocas777/kairos"
+	"math"
+)
+
+// GaussKronrod provides a method to calculate the definite integral of a given function using adaptive [Gauss-Kronrod] quadrature,
+// in the spirit of the QUADPACK QAGS routine.
+// Each subinterval is integrated with a Kronrod rule and with the Gauss rule embedded in it; their difference is used as the error estimate.
+// The subintervals are kept in a priority queue ordered by their error, and the worst one is bisected until the total estimated error
+// is below max('AbsTolerance', 'RelTolerance' * |integral|) or the evaluation budget 'MaxEvaluations' is exhausted.
+//
+// 'Points' selects the pair of rules used: 15 for the G7-K15 pair and 21 for the G10-K21 pair.
+//
+// When 'Extrapolate' is set, the sequence of integral estimates obtained each time the smallest subinterval is halved is accelerated
+// with the [Wynn epsilon] algorithm. This greatly reduces the number of evaluations needed for integrable singularities at the endpoints.
+//
+// If neither 'AbsTolerance' nor 'RelTolerance' is specified, 'AbsTolerance' defaults to 1e-8. If any of them is less than 0, a panic is raised.
+//
+// If 'MaxEvaluations' is not specified, it defaults to 10000.
+//
+// If 'Points' is not specified, it defaults to 21. If it is neither 15 nor 21, a panic is raised.
+//
+// [Gauss-Kronrod]: https://en.wikipedia.org/wiki/Gauss%E2%80%93Kronrod_quadrature_formula
+// [Wynn epsilon]: https://en.wikipedia.org/wiki/Shanks_transformation
+type GaussKronrod struct {
+	AbsTolerance   float64
+	RelTolerance   float64
+	MaxEvaluations uint
+	Points         uint
+	Extrapolate    bool
+	cycles         uint
+}
+
+// NewGaussKronrod creates and returns a pointer to a new [GaussKronrod] instance using the G10-K21 pair with the specified tolerances.
+//
+// If 'absTolerance' or 'relTolerance' is below 0, a panic is raised.
+func NewGaussKronrod(absTolerance, relTolerance float64) *GaussKronrod {
+	return &GaussKronrod{AbsTolerance: absTolerance, RelTolerance: relTolerance, Points: 21}
+}
+
+func (g *GaussKronrod) Cycles() uint {
+	return g.cycles
+}
+
+// DefiniteIntegral calculates the definite integral of the given function 'f' using adaptive Gauss-Kronrod quadrature.
+// It returns the best estimate found, even if the requested tolerance could not be reached; use [GaussKronrod.DefiniteIntegralResult]
+// to also get the estimated error.
+//
+// The function 'f' represents the integrand, and 'a' and 'b' define the integration interval.
+// The result is returned as a float64 representing the calculated definite integral.
+func (g *GaussKronrod) DefiniteIntegral(f func(x float64) float64, a, b float64) float64 {
+	r, _ := g.DefiniteIntegralResult(f, a, b)
+	return r.Value
+}
+
+// DefiniteIntegralResult works like [GaussKronrod.DefiniteIntegral] but returns a [Result] holding the integral, its estimated error
+// and the number of evaluations. The error is [ErrMaxEvaluations] if the tolerance could not be reached within 'MaxEvaluations'
+// and [ErrNonFinite] if 'f' returns NaN or ±Inf.
+func (g *GaussKronrod) DefiniteIntegralResult(f func(x float64) float64, a, b float64) (Result, error) {
+	g.handleInput()
+
+	g.cycles = 0
+	rule := kronrod21
+	if g.Points == 15 {
+		rule = kronrod15
+	}
+	eval := func(a, b float64, depth uint) kronrodInterval {
+		k, gauss := rule.apply(f, a, b)
+		g.cycles += rule.evaluations()
+		return kronrodInterval{a: a, b: b, value: k, err: math.Abs(k - gauss), depth: depth}
+	}
+
+	first := eval(a, b, 0)
+	if !finite(first.value) {
+		return Result{Value: first.value, ErrorEstimate: first.err, Evaluations: g.cycles}, ErrNonFinite
+	}
+	queue := &kronrodQueue{first}
+	value, err := first.value, first.err
+
+	var sequence []float64
+	extrapolated := Result{ErrorEstimate: math.Inf(1)}
+	maxDepth := uint(0)
+	for err > g.tolerance(value) && extrapolated.ErrorEstimate > g.tolerance(extrapolated.Value) {
+		if g.cycles+2*rule.evaluations() > g.MaxEvaluations {
+			return g.best(queue, err, extrapolated), ErrMaxEvaluations
+		}
+		worst := heap.Pop(queue).(kronrodInterval)
+		mid := (worst.a + worst.b) / 2
+		left := eval(worst.a, mid, worst.depth+1)
+		right := eval(mid, worst.b, worst.depth+1)
+		if !finite(left.value) || !finite(right.value) {
+			heap.Push(queue, worst)
+			return g.best(queue, err, extrapolated), ErrNonFinite
+		}
+		heap.Push(queue, left)
+		heap.Push(queue, right)
+		value += left.value + right.value - worst.value
+		err += left.err + right.err - worst.err
+
+		if g.Extrapolate && worst.depth+1 > maxDepth {
+			maxDepth = worst.depth + 1
+			sequence = append(sequence, queue.sum())
+			extrapolated.Value, extrapolated.ErrorEstimate = wynnEpsilon(sequence)
+		}
+	}
+	return g.best(queue, err, extrapolated), nil
+}
+
+// best picks between the plain sum of the subintervals and the extrapolated estimate, whichever has the smallest error.
+func (g *GaussKronrod) best(queue *kronrodQueue, err float64, extrapolated Result) Result {
+	if extrapolated.ErrorEstimate < err {
+		extrapolated.Evaluations = g.cycles
+		return extrapolated
+	}
+	// summing again avoids the rounding errors accumulated by the incremental updates
+	return Result{Value: queue.sum(), ErrorEstimate: err, Evaluations: g.cycles}
+}
+
+// AntiDerivative calculates the approximate antiderivative of the given function 'f' using adaptive Gauss-Kronrod quadrature.
+// It samples the antiderivative at 'samples' points within the interval [a, b].
+// The antiderivative is the integral from 0 to each sampled point 'x'. It integrates from 0 to 'a' once, then only the panel between
+// each sample and the previous one, with its share of 'AbsTolerance', so the cost grows linearly with the number of samples.
+// [GaussKronrod.Cycles] then reports the total number of evaluations of 'f'.
+//
+// If 'samples' is less than 2, it defaults to 2.
+//
+// The function 'f' represents the integrand, and 'a' and 'b' define the integration interval.
+// The result is returned as a slice of [kairos.Pair] representing the sampled points and their corresponding antiderivative values.
+func (g *GaussKronrod) AntiDerivative(f func(x float64) float64, a, b float64, samples uint) []kairos.Pair {
+	g.handleInput()
+	if samples < 2 {
+		samples = 2
+	}
+	panel := &GaussKronrod{AbsTolerance: g.AbsTolerance * panelShare(samples), RelTolerance: g.RelTolerance, MaxEvaluations: g.MaxEvaluations, Points: g.Points, Extrapolate: g.Extrapolate}
+	out, evaluations := antiDerivative(g.DefiniteIntegral, panel.DefiniteIntegral, f, a, b, samples)
+	g.cycles = evaluations
+	return out
+}
+
+func (g *GaussKronrod) tolerance(value float64) float64 {
+	return math.Max(g.AbsTolerance, g.RelTolerance*math.Abs(value))
+}
+
+func (g *GaussKronrod) handleInput() {
+	if g.AbsTolerance < 0 {
+		panic("GaussKronrod struct value of AbsTolerance should be higher than 0")
+	}
+	if g.RelTolerance < 0 {
+		panic("GaussKronrod struct value of RelTolerance should be higher than 0")
+	}
+	if g.AbsTolerance == 0 && g.RelTolerance == 0 {
+		g.AbsTolerance = 1e-8
+	}
+	if g.MaxEvaluations == 0 {
+		g.MaxEvaluations = 10000
+	}
+	if g.Points == 0 {
+		g.Points = 21
+	} else if g.Points != 15 && g.Points != 21 {
+		panic("GaussKronrod struct value of Points should be 15 or 21")
+	}
+}
+
+// kronrodRule holds the nodes of a Kronrod rule on [0, 1] in decreasing order, ending with the center node.
+// The Gauss nodes are the ones with an odd index, and 'wg' holds their weights.
+type kronrodRule struct {
+	xgk []float64
+	wgk []float64
+	wg  []float64
+}
+
+// apply integrates 'f' over [a, b] with both the Kronrod and the embedded Gauss rules.
+func (r kronrodRule) apply(f func(x float64) float64, a, b float64) (kronrod, gauss float64) {
+	center := (a + b) / 2
+	h := (b - a) / 2
+	last := len(r.xgk) - 1
+	fc := f(center)
+	kronrod = r.wgk[last] * fc
+	if last%2 == 1 {
+		gauss = r.wg[last/2] * fc
+	}
+	for j := 0; j < last; j++ {
+		dx := h * r.xgk[j]
+		sum := f(center-dx) + f(center+dx)
+		kronrod += r.wgk[j] * sum
+		if j%2 == 1 {
+			gauss += r.wg[j/2] * sum
+		}
+	}
+	return kronrod * h, gauss * h
+}
+
+func (r kronrodRule) evaluations() uint {
+	return uint(2*len(r.xgk) - 1)
+}
+
+var kronrod15 = kronrodRule{
+	xgk: []float64{
+		0.991455371120812639206854697526329,
+		0.949107912342758524526189684047851,
+		0.864864423359769072789712788640926,
+		0.741531185599394439863864773280788,
+		0.586087235467691130294144845693013,
+		0.405845151377397166906606412076961,
+		0.207784955007898467600689403773245,
+		0.000000000000000000000000000000000,
+	},
+	wgk: []float64{
+		0.022935322010529224963732008058970,
+		0.063092092629978553290700663189204,
+		0.104790010322250183839876322541518,
+		0.140653259715525918745189590510238,
+		0.169004726639267902826583426598550,
+		0.190350578064785409913256402421014,
+		0.204432940075298892414161999234649,
+		0.209482141084727828012999174891714,
+	},
+	wg: []float64{
+		0.129484966168869693270611432679082,
+		0.279705391489276667901467771423780,
+		0.381830050505118944950369775488975,
+		0.417959183673469387755102040816327,
+	},
+}
+
+var kronrod21 = kronrodRule{
+	xgk: []float64{
+		0.995657163025808080735527280689003,
+		0.973906528517171720077964012084452,
+		0.930157491355708226001207180059508,
+		0.865063366688984510732096688423493,
+		0.780817726586416897063717578345042,
+		0.679409568299024406234327365114874,
+		0.562757134668604683339000099272694,
+		0.433395394129247190799265943165784,
+		0.294392862701460198131126603103866,
+		0.148874338981631210884826001129720,
+		0.000000000000000000000000000000000,
+	},
+	wgk: []float64{
+		0.011694638867371874278064396062192,
+		0.032558162307964727478818972459390,
+		0.054755896574351996031381300244580,
+		0.075039674810919952767043140916190,
+		0.093125454583697605535065465083366,
+		0.109387158802297641899210590325805,
+		0.123491976262065851077208980109900,
+		0.134709217311473325928054001771707,
+		0.142775938577060080797094273138717,
+		0.147739104901338491374841515972068,
+		0.149445554002916905664936468389821,
+	},
+	wg: []float64{
+		0.066671344308688137593568809893332,
+		0.149451349150580593145776339657697,
+		0.219086362515982043995534934228163,
+		0.269266719309996355091226921569469,
+		0.295524224714752870173892994651338,
+	},
+}
+
+type kronrodInterval struct {
+	a, b       float64
+	value, err float64
+	depth      uint
+}
+
+// kronrodQueue is a max-heap of subintervals ordered by their estimated error.
+type kronrodQueue []kronrodInterval
+
+func (q kronrodQueue) Len() int           { return len(q) }
+func (q kronrodQueue) Less(i, j int) bool { return q[i].err > q[j].err }
+func (q kronrodQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *kronrodQueue) Push(x any)        { *q = append(*q, x.(kronrodInterval)) }
+func (q *kronrodQueue) Pop() any {
+	old := *q
+	n := len(old)
+	item := old[n-1]
+	*q = old[:n-1]
+	return item
+}
+
+func (q kronrodQueue) sum() float64 {
+	out := 0.0
+	for _, interval := range q {
+		out += interval.value
+	}
+	return out
+}
+
+// wynnEpsilon accelerates the convergence of the sequence 's' with the Wynn epsilon algorithm.
+// It returns the best extrapolated value and an error estimate based on the difference between the last two entries of its column.
+func wynnEpsilon(s []float64) (value, err float64) {
+	n := len(s)
+	value, err = s[n-1], math.Inf(1)
+	if n < 3 {
+		return value, err
+	}
+	prev := make([]float64, n+1)
+	cur := append([]float64(nil), s...)
+	for column := 1; len(cur) > 1; column++ {
+		next := make([]float64, len(cur)-1)
+		for k := range next {
+			diff := cur[k+1] - cur[k]
+			if diff == 0 || !finite(1/diff) {
+				return value, err
+			}
+			next[k] = prev[k+1] + 1/diff
+		}
+		prev, cur = cur, next
+		if column%2 == 0 && len(cur) >= 2 {
+			candidateErr := math.Abs(cur[len(cur)-1] - cur[len(cur)-2])
+			if candidateErr < err {
+				value, err = cur[len(cur)-1], candidateErr
+			}
+		}
+	}
+	return value, err
+}
@@ -0,0 +1,432 @@
+package integration
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+// GaussHermite provides a method to calculate integrals of the form ∫ e^{-x²}·f(x) dx over the whole real line using [Gauss-Hermite] quadrature,
+// without truncating the range. The N point rule is exact when 'f' is a polynomial of degree up to 2N-1.
+// It is the natural way to compute expectations under a normal distribution, see [GaussHermite.Expectation].
+//
+// The nodes and weights are computed with the Golub-Welsch algorithm and cached for every N, so they are only calculated once.
+//
+// If N is not specified, it defaults to 10.
+//
+// [Gauss-Hermite]: https://en.wikipedia.org/wiki/Gauss%E2%80%93Hermite_quadrature
+type GaussHermite struct {
+	N      uint
+	cycles uint
+}
+
+// NewGaussHermite creates and returns a pointer to a new [GaussHermite] instance using 'n' nodes.
+func NewGaussHermite(n uint) *GaussHermite {
+	return &GaussHermite{N: n}
+}
+
+func (g *GaussHermite) Cycles() uint {
+	return g.cycles
+}
+
+// WeightedIntegral calculates the integral of e^{-x²}·f(x) over (-Inf, +Inf).
+//
+// The function 'f' represents the integrand without its weight.
+// The result is returned as a float64 representing the calculated integral.
+func (g *GaussHermite) WeightedIntegral(f func(x float64) float64) float64 {
+	g.handleInput()
+	nodes, weights := hermiteRule(g.N)
+	return weightedSum(f, nodes, weights, func(x float64) float64 { return x }, &g.cycles)
+}
+
+// Expectation calculates the expected value of f(X), where X is normally distributed with the given 'mean' and standard 'deviation'.
+func (g *GaussHermite) Expectation(f func(x float64) float64, mean, deviation float64) float64 {
+	g.handleInput()
+	nodes, weights := hermiteRule(g.N)
+	scale := math.Sqrt2 * deviation
+	return weightedSum(f, nodes, weights, func(x float64) float64 { return mean + scale*x }, &g.cycles) / math.Sqrt(math.Pi)
+}
+
+// Rule returns a copy of the nodes and weights of the N point Gauss-Hermite rule.
+func (g *GaussHermite) Rule() (nodes, weights []float64) {
+	g.handleInput()
+	return copyRule(hermiteRule(g.N))
+}
+
+func (g *GaussHermite) handleInput() {
+	if g.N == 0 {
+		g.N = 10
+	}
+}
+
+// GaussLaguerre provides a method to calculate integrals of the form ∫ x^α·e^{-x}·f(x) dx over [0, +Inf), where α is 'Alpha',
+// using generalized [Gauss-Laguerre] quadrature, without truncating the range. The N point rule is exact when 'f' is a polynomial
+// of degree up to 2N-1. It is the natural way to compute expectations under exponential and gamma distributions, see [GaussLaguerre.Expectation].
+//
+// The nodes and weights are computed with the Golub-Welsch algorithm and cached for every N and 'Alpha', so they are only calculated once.
+//
+// If N is not specified, it defaults to 10. 'Alpha' defaults to 0, which is the classic Gauss-Laguerre rule. If 'Alpha' is not higher than -1, a panic will be raised.
+//
+// [Gauss-Laguerre]: https://en.wikipedia.org/wiki/Gauss%E2%80%93Laguerre_quadrature
+type GaussLaguerre struct {
+	N      uint
+	Alpha  float64
+	cycles uint
+}
+
+// NewGaussLaguerre creates and returns a pointer to a new [GaussLaguerre] instance using 'n' nodes and the weight x^alpha·e^{-x}.
+//
+// If 'alpha' is not higher than -1, a panic is raised.
+func NewGaussLaguerre(n uint, alpha float64) *GaussLaguerre {
+	return &GaussLaguerre{N: n, Alpha: alpha}
+}
+
+func (g *GaussLaguerre) Cycles() uint {
+	return g.cycles
+}
+
+// WeightedIntegral calculates the integral of x^α·e^{-x}·f(x) over [0, +Inf).
+//
+// The function 'f' represents the integrand without its weight.
+// The result is returned as a float64 representing the calculated integral.
+func (g *GaussLaguerre) WeightedIntegral(f func(x float64) float64) float64 {
+	g.handleInput()
+	nodes, weights := laguerreRule(g.N, g.Alpha)
+	return weightedSum(f, nodes, weights, func(x float64) float64 { return x }, &g.cycles)
+}
+
+// Expectation calculates the expected value of f(X), where X follows a gamma distribution with shape α+1 and the given 'rate'.
+// With the default 'Alpha' of 0, X is exponentially distributed.
+func (g *GaussLaguerre) Expectation(f func(x float64) float64, rate float64) float64 {
+	g.handleInput()
+	nodes, weights := laguerreRule(g.N, g.Alpha)
+	return weightedSum(f, nodes, weights, func(x float64) float64 { return x / rate }, &g.cycles) / math.Gamma(g.Alpha+1)
+}
+
+// Rule returns a copy of the nodes and weights of the N point Gauss-Laguerre rule.
+func (g *GaussLaguerre) Rule() (nodes, weights []float64) {
+	g.handleInput()
+	return copyRule(laguerreRule(g.N, g.Alpha))
+}
+
+func (g *GaussLaguerre) handleInput() {
+	if g.N == 0 {
+		g.N = 10
+	}
+	if g.Alpha <= -1 {
+		panic("GaussLaguerre struct value of Alpha should be higher than -1")
+	}
+}
+
+// GaussJacobi provides a method to calculate integrals of the form ∫ (1-x)^α·(1+x)^β·f(x) dx over [-1, 1], where α is 'Alpha' and β is 'Beta',
+// using [Gauss-Jacobi] quadrature. The weight absorbs algebraic endpoint singularities, which are then integrated exactly,
+// and the N point rule is exact when 'f' is a polynomial of degree up to 2N-1.
+//
+// The nodes and weights are computed with the Golub-Welsch algorithm and cached for every N, 'Alpha' and 'Beta', so they are only calculated once.
+//
+// If N is not specified, it defaults to 10. 'Alpha' and 'Beta' default to 0, which gives the Gauss-Legendre rule.
+// If any of them is not higher than -1, a panic will be raised.
+//
+// [Gauss-Jacobi]: https://en.wikipedia.org/wiki/Gauss%E2%80%93Jacobi_quadrature
+type GaussJacobi struct {
+	N      uint
+	Alpha  float64
+	Beta   float64
+	cycles uint
+}
+
+// NewGaussJacobi creates and returns a pointer to a new [GaussJacobi] instance using 'n' nodes and the weight (1-x)^alpha·(1+x)^beta.
+//
+// If 'alpha' or 'beta' is not higher than -1, a panic is raised.
+func NewGaussJacobi(n uint, alpha, beta float64) *GaussJacobi {
+	return &GaussJacobi{N: n, Alpha: alpha, Beta: beta}
+}
+
+func (g *GaussJacobi) Cycles() uint {
+	return g.cycles
+}
+
+// WeightedIntegral calculates the integral of (b-x)^α·(x-a)^β·f(x) over [a, b], which is the Jacobi weight mapped onto [a, b].
+//
+// The function 'f' represents the integrand without its weight, and 'a' and 'b' define the integration interval.
+// The result is returned as a float64 representing the calculated integral.
+func (g *GaussJacobi) WeightedIntegral(f func(x float64) float64, a, b float64) float64 {
+	g.handleInput()
+	nodes, weights := jacobiRule(g.N, g.Alpha, g.Beta)
+	h := (b - a) / 2
+	out := weightedSum(f, nodes, weights, func(x float64) float64 { return a + h*(x+1) }, &g.cycles)
+	return out * math.Pow(h, g.Alpha+g.Beta+1)
+}
+
+// Rule returns a copy of the nodes and weights of the N point Gauss-Jacobi rule on [-1, 1].
+func (g *GaussJacobi) Rule() (nodes, weights []float64) {
+	g.handleInput()
+	return copyRule(jacobiRule(g.N, g.Alpha, g.Beta))
+}
+
+func (g *GaussJacobi) handleInput() {
+	if g.N == 0 {
+		g.N = 10
+	}
+	if g.Alpha <= -1 {
+		panic("GaussJacobi struct value of Alpha should be higher than -1")
+	}
+	if g.Beta <= -1 {
+		panic("GaussJacobi struct value of Beta should be higher than -1")
+	}
+}
+
+// ChebyshevKind selects the weight of [GaussChebyshev].
+type ChebyshevKind int
+
+const (
+	// ChebyshevFirstKind uses the weight 1/sqrt(1-x²).
+	ChebyshevFirstKind ChebyshevKind = iota
+	// ChebyshevSecondKind uses the weight sqrt(1-x²).
+	ChebyshevSecondKind
+)
+
+// GaussChebyshev provides a method to calculate integrals of the form ∫ w(x)·f(x) dx over [-1, 1] using [Gauss-Chebyshev] quadrature,
+// where w(x) is 1/sqrt(1-x²) or sqrt(1-x²) depending on 'Kind'. The N point rule is exact when 'f' is a polynomial of degree up to 2N-1.
+//
+// The nodes and weights are known in closed form, and are cached for every N and 'Kind'.
+//
+// If N is not specified, it defaults to 10. 'Kind' defaults to [ChebyshevFirstKind].
+//
+// [Gauss-Chebyshev]: https://en.wikipedia.org/wiki/Chebyshev%E2%80%93Gauss_quadrature
+type GaussChebyshev struct {
+	N      uint
+	Kind   ChebyshevKind
+	cycles uint
+}
+
+// NewGaussChebyshev creates and returns a pointer to a new [GaussChebyshev] instance using 'n' nodes and the weight of the given 'kind'.
+func NewGaussChebyshev(n uint, kind ChebyshevKind) *GaussChebyshev {
+	return &GaussChebyshev{N: n, Kind: kind}
+}
+
+func (g *GaussChebyshev) Cycles() uint {
+	return g.cycles
+}
+
+// WeightedIntegral calculates the integral of w(x)·f(x) over [a, b], where the weight is mapped onto [a, b]:
+// 1/sqrt((x-a)(b-x)) for [ChebyshevFirstKind] and sqrt((x-a)(b-x)) for [ChebyshevSecondKind].
+//
+// The function 'f' represents the integrand without its weight, and 'a' and 'b' define the integration interval.
+// The result is returned as a float64 representing the calculated integral.
+func (g *GaussChebyshev) WeightedIntegral(f func(x float64) float64, a, b float64) float64 {
+	g.handleInput()
+	nodes, weights := chebyshevRule(g.N, g.Kind)
+	h := (b - a) / 2
+	out := weightedSum(f, nodes, weights, func(x float64) float64 { return a + h*(x+1) }, &g.cycles)
+	if g.Kind == ChebyshevSecondKind {
+		return out * h * h
+	}
+	return out
+}
+
+// Rule returns a copy of the nodes and weights of the N point Gauss-Chebyshev rule on [-1, 1].
+func (g *GaussChebyshev) Rule() (nodes, weights []float64) {
+	g.handleInput()
+	return copyRule(chebyshevRule(g.N, g.Kind))
+}
+
+func (g *GaussChebyshev) handleInput() {
+	if g.N == 0 {
+		g.N = 10
+	}
+}
+
+// weightedSum returns the sum of the weights times 'f' at the nodes mapped by 'x', counting every evaluation in 'cycles'.
+func weightedSum(f func(x float64) float64, nodes, weights []float64, x func(node float64) float64, cycles *uint) float64 {
+	*cycles = 0
+	out := 0.0
+	for i := range nodes {
+		*cycles++
+		out += weights[i] * f(x(nodes[i]))
+	}
+	return out
+}
+
+func copyRule(nodes, weights []float64) ([]float64, []float64) {
+	return append([]float64(nil), nodes...), append([]float64(nil), weights...)
+}
+
+type gaussRuleKey struct {
+	family      string
+	n           uint
+	alpha, beta float64
+}
+
+var (
+	gaussRuleMutex sync.Mutex
+	gaussRuleCache = map[gaussRuleKey][2][]float64{}
+)
+
+// cachedRule returns the rule stored under 'key', computing it with 'compute' the first time.
+// The returned slices are shared between callers and must not be modified.
+func cachedRule(key gaussRuleKey, compute func() (nodes, weights []float64)) ([]float64, []float64) {
+	gaussRuleMutex.Lock()
+	defer gaussRuleMutex.Unlock()
+	if rule, ok := gaussRuleCache[key]; ok {
+		return rule[0], rule[1]
+	}
+	nodes, weights := compute()
+	gaussRuleCache[key] = [2][]float64{nodes, weights}
+	return nodes, weights
+}
+
+// hermiteRule returns the nodes and weights of the n point Gauss-Hermite rule.
+func hermiteRule(n uint) ([]float64, []float64) {
+	return cachedRule(gaussRuleKey{family: "hermite", n: n}, func() ([]float64, []float64) {
+		alpha := make([]float64, n)
+		beta := make([]float64, n)
+		for k := 1; k < int(n); k++ {
+			beta[k] = float64(k) / 2
+		}
+		return golubWelsch(alpha, beta, math.Sqrt(math.Pi))
+	})
+}
+
+// laguerreRule returns the nodes and weights of the n point generalized Gauss-Laguerre rule for the weight x^a·e^{-x}.
+func laguerreRule(n uint, a float64) ([]float64, []float64) {
+	return cachedRule(gaussRuleKey{family: "laguerre", n: n, alpha: a}, func() ([]float64, []float64) {
+		alpha := make([]float64, n)
+		beta := make([]float64, n)
+		for k := 0; k < int(n); k++ {
+			alpha[k] = float64(2*k+1) + a
+			beta[k] = float64(k) * (float64(k) + a)
+		}
+		return golubWelsch(alpha, beta, math.Gamma(a+1))
+	})
+}
+
+// jacobiRule returns the nodes and weights of the n point Gauss-Jacobi rule for the weight (1-x)^a·(1+x)^b.
+func jacobiRule(n uint, a, b float64) ([]float64, []float64) {
+	return cachedRule(gaussRuleKey{family: "jacobi", n: n, alpha: a, beta: b}, func() ([]float64, []float64) {
+		alpha := make([]float64, n)
+		beta := make([]float64, n)
+		for k := 0; k < int(n); k++ {
+			s := float64(2*k) + a + b
+			switch k {
+			case 0:
+				alpha[k] = (b - a) / (a + b + 2)
+			default:
+				alpha[k] = (b*b - a*a) / (s * (s + 2))
+			}
+			switch k {
+			case 0:
+			case 1:
+				// the general expression is 0/0 when a+b = -1
+				beta[k] = 4 * (1 + a) * (1 + b) / ((2 + a + b) * (2 + a + b) * (3 + a + b))
+			default:
+				kf := float64(k)
+				beta[k] = 4 * kf * (kf + a) * (kf + b) * (kf + a + b) / (s * s * (s + 1) * (s - 1))
+			}
+		}
+		lgammaA, _ := math.Lgamma(a + 1)
+		lgammaB, _ := math.Lgamma(b + 1)
+		lgammaAB, _ := math.Lgamma(a + b + 2)
+		mu0 := math.Exp((a+b+1)*math.Ln2 + lgammaA + lgammaB - lgammaAB)
+		return golubWelsch(alpha, beta, mu0)
+	})
+}
+
+// chebyshevRule returns the nodes and weights of the n point Gauss-Chebyshev rule of the given kind, in increasing order of the nodes.
+func chebyshevRule(n uint, kind ChebyshevKind) ([]float64, []float64) {
+	return cachedRule(gaussRuleKey{family: "chebyshev", n: n, alpha: float64(kind)}, func() ([]float64, []float64) {
+		nodes := make([]float64, n)
+		weights := make([]float64, n)
+		for i := range nodes {
+			if kind == ChebyshevSecondKind {
+				theta := math.Pi * float64(int(n)-i) / float64(n+1)
+				nodes[i] = math.Cos(theta)
+				weights[i] = math.Pi / float64(n+1) * math.Sin(theta) * math.Sin(theta)
+			} else {
+				nodes[i] = math.Cos(math.Pi * float64(2*(int(n)-i)-1) / float64(2*n))
+				weights[i] = math.Pi / float64(n)
+			}
+		}
+		return nodes, weights
+	})
+}
+
+// golubWelsch returns the nodes and weights of the Gaussian rule whose monic orthogonal polynomials satisfy
+// p_{k+1}(x) = (x - alpha[k])·p_k(x) - beta[k]·p_{k-1}(x), with 'mu0' the integral of the weight, using the [Golub-Welsch] algorithm.
+// The nodes are the eigenvalues of the symmetric tridiagonal Jacobi matrix, found with the implicit QL method, and the weights are
+// 'mu0' times the squared first components of its normalized eigenvectors. Only those components are tracked through the rotations.
+//
+// [Golub-Welsch]: https://en.wikipedia.org/wiki/Gaussian_quadrature#The_Golub-Welsch_algorithm
+func golubWelsch(alpha, beta []float64, mu0 float64) (nodes, weights []float64) {
+	n := len(alpha)
+	d := append([]float64(nil), alpha...)
+	// e[i] couples the rows i and i+1 of the Jacobi matrix
+	e := make([]float64, n)
+	for i := 0; i+1 < n; i++ {
+		e[i] = math.Sqrt(beta[i+1])
+	}
+	z := make([]float64, n)
+	if n > 0 {
+		z[0] = 1
+	}
+
+	for l := 0; l < n; l++ {
+		for iter := 0; iter < 100; iter++ {
+			m := l
+			for ; m < n-1; m++ {
+				dd := math.Abs(d[m]) + math.Abs(d[m+1])
+				if math.Abs(e[m])+dd == dd {
+					break
+				}
+			}
+			if m == l {
+				break
+			}
+			g := (d[l+1] - d[l]) / (2 * e[l])
+			r := math.Hypot(g, 1)
+			g = d[m] - d[l] + e[l]/(g+math.Copysign(r, g))
+			s, c, p := 1.0, 1.0, 0.0
+			i := m - 1
+			for ; i >= l; i-- {
+				f := s * e[i]
+				b := c * e[i]
+				r = math.Hypot(f, g)
+				e[i+1] = r
+				if r == 0 {
+					d[i+1] -= p
+					e[m] = 0
+					break
+				}
+				s = f / r
+				c = g / r
+				g = d[i+1] - p
+				r = (d[i]-g)*s + 2*c*b
+				p = s * r
+				d[i+1] = g + p
+				g = c*r - b
+				f = z[i+1]
+				z[i+1] = s*z[i] + c*f
+				z[i] = c*z[i] - s*f
+			}
+			if r == 0 && i >= l {
+				continue
+			}
+			d[l] -= p
+			e[l] = g
+			e[m] = 0
+		}
+	}
+
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool { return d[order[i]] < d[order[j]] })
+	nodes = make([]float64, n)
+	weights = make([]float64, n)
+	for i, k := range order {
+		nodes[i] = d[k]
+		weights[i] = mu0 * z[k] * z[k]
+	}
+	return nodes, weights
+}
@@ -0,0 +1,250 @@
+package integration
+
+import (
+	"container/heap"
+	"math"
+)
+
+// GenzMalik provides a method to calculate the definite integral of a function of several variables over a box
+// using adaptive [Genz-Malik] cubature.
+// Each sub-box is integrated with a degree 7 rule and with the degree 5 rule embedded in it; their difference is used as the error estimate.
+// The sub-boxes are kept in a priority queue ordered by their error, and the worst one is bisected along the axis where the function
+// has the largest fourth difference, until the total estimated error is below max('AbsTolerance', 'RelTolerance' * |integral|)
+// or the evaluation budget 'MaxEvaluations' is exhausted.
+//
+// Each sub-box costs 2^d + 2d² + 2d + 1 evaluations, where d is the number of dimensions, which must be at least 2.
+//
+// If neither 'AbsTolerance' nor 'RelTolerance' is specified, 'AbsTolerance' defaults to 1e-6. If any of them is less than 0, a panic is raised.
+//
+// If 'MaxEvaluations' is not specified, it defaults to 100000.
+//
+// [Genz-Malik]: https://doi.org/10.1016/0771-050X(80)90039-X
+type GenzMalik struct {
+	AbsTolerance   float64
+	RelTolerance   float64
+	MaxEvaluations uint
+	cycles         uint
+}
+
+// NewGenzMalik creates and returns a pointer to a new [GenzMalik] instance with the specified tolerances.
+//
+// If 'absTolerance' or 'relTolerance' is below 0, a panic is raised.
+func NewGenzMalik(absTolerance, relTolerance float64) *GenzMalik {
+	return &GenzMalik{AbsTolerance: absTolerance, RelTolerance: relTolerance}
+}
+
+func (g *GenzMalik) Cycles() uint {
+	return g.cycles
+}
+
+// DefiniteIntegral calculates the definite integral of the given function 'f' over the box [a, b] using adaptive Genz-Malik cubature.
+// It returns the best estimate found, even if the requested tolerance could not be reached; use [GenzMalik.DefiniteIntegralResult]
+// to also get the estimated error.
+//
+// The function 'f' represents the integrand, and 'a' and 'b' hold the lower and upper bounds of each dimension.
+// The result is returned as a float64 representing the calculated definite integral.
+func (g *GenzMalik) DefiniteIntegral(f func(x []float64) float64, a, b []float64) float64 {
+	r, _ := g.DefiniteIntegralResult(f, a, b)
+	return r.Value
+}
+
+// DefiniteIntegralResult works like [GenzMalik.DefiniteIntegral] but returns a [Result] holding the integral, its estimated error
+// and the number of evaluations. The error is [ErrMaxEvaluations] if the tolerance could not be reached within 'MaxEvaluations'
+// and [ErrNonFinite] if 'f' returns NaN or ±Inf.
+func (g *GenzMalik) DefiniteIntegralResult(f func(x []float64) float64, a, b []float64) (Result, error) {
+	g.handleInput()
+	checkBox(a, b)
+	if len(a) < 2 {
+		panic("GenzMalik requires at least 2 dimensions")
+	}
+
+	g.cycles = 0
+	rule := newGenzMalikRule(len(a))
+	center := make([]float64, len(a))
+	half := make([]float64, len(a))
+	for i := range a {
+		center[i] = (a[i] + b[i]) / 2
+		half[i] = (b[i] - a[i]) / 2
+	}
+	first := rule.apply(f, center, half)
+	g.cycles += rule.evaluations
+	queue := &genzMalikQueue{first}
+	value, err := first.value, first.err
+	for err > math.Max(g.AbsTolerance, g.RelTolerance*math.Abs(value)) {
+		if !finite(value) {
+			return Result{Value: value, ErrorEstimate: err, Evaluations: g.cycles}, ErrNonFinite
+		}
+		if g.cycles+2*rule.evaluations > g.MaxEvaluations {
+			return Result{Value: queue.sum(), ErrorEstimate: err, Evaluations: g.cycles}, ErrMaxEvaluations
+		}
+		worst := heap.Pop(queue).(genzMalikBox)
+		axis := worst.split
+		half := append([]float64(nil), worst.half...)
+		half[axis] /= 2
+		for _, side := range []float64{-1, 1} {
+			center := append([]float64(nil), worst.center...)
+			center[axis] += side * half[axis]
+			box := rule.apply(f, center, half)
+			g.cycles += rule.evaluations
+			heap.Push(queue, box)
+			value += box.value
+			err += box.err
+		}
+		value -= worst.value
+		err -= worst.err
+	}
+	if !finite(value) {
+		return Result{Value: value, ErrorEstimate: err, Evaluations: g.cycles}, ErrNonFinite
+	}
+	return Result{Value: queue.sum(), ErrorEstimate: err, Evaluations: g.cycles}, nil
+}
+
+func (g *GenzMalik) handleInput() {
+	if g.AbsTolerance < 0 {
+		panic("GenzMalik struct value of AbsTolerance should be higher than 0")
+	}
+	if g.RelTolerance < 0 {
+		panic("GenzMalik struct value of RelTolerance should be higher than 0")
+	}
+	if g.AbsTolerance == 0 && g.RelTolerance == 0 {
+		g.AbsTolerance = 1e-6
+	}
+	if g.MaxEvaluations == 0 {
+		g.MaxEvaluations = 100000
+	}
+}
+
+// genzMalikRule holds the weights of the degree 7 rule (w7) and of the embedded degree 5 rule (w5) for a given dimension.
+type genzMalikRule struct {
+	d           int
+	w7, w5      [5]float64
+	evaluations uint
+}
+
+const (
+	genzMalikLambda2 = 0.35856858280031809 // sqrt(9/70)
+	genzMalikLambda4 = 0.94868329805051379 // sqrt(9/10)
+	genzMalikLambda5 = 0.68824720161168529 // sqrt(9/19)
+)
+
+func newGenzMalikRule(d int) genzMalikRule {
+	n := float64(d)
+	return genzMalikRule{
+		d: d,
+		w7: [5]float64{
+			(12824 - 9120*n + 400*n*n) / 19683,
+			980.0 / 6561,
+			(1820 - 400*n) / 19683,
+			200.0 / 19683,
+			6859.0 / 19683 / math.Pow(2, n),
+		},
+		w5: [5]float64{
+			(729 - 950*n + 50*n*n) / 729,
+			245.0 / 486,
+			(265 - 100*n) / 1458,
+			25.0 / 729,
+		},
+		evaluations: uint(1<<d + 2*d*d + 2*d + 1),
+	}
+}
+
+// apply integrates 'f' over the box with the given center and half widths, and picks the axis along which the box should be split.
+func (r genzMalikRule) apply(f func(x []float64) float64, center, half []float64) genzMalikBox {
+	x := append([]float64(nil), center...)
+
+	var sums [5]float64
+	fc := f(x)
+	sums[0] = fc
+
+	split, largest := 0, -1.0
+	ratio := genzMalikLambda2 * genzMalikLambda2 / (genzMalikLambda4 * genzMalikLambda4)
+	for i := 0; i < r.d; i++ {
+		x[i] = center[i] + genzMalikLambda2*half[i]
+		f2 := f(x)
+		x[i] = center[i] - genzMalikLambda2*half[i]
+		f2 += f(x)
+		x[i] = center[i] + genzMalikLambda4*half[i]
+		f3 := f(x)
+		x[i] = center[i] - genzMalikLambda4*half[i]
+		f3 += f(x)
+		x[i] = center[i]
+		sums[1] += f2
+		sums[2] += f3
+
+		// fourth divided difference along axis i
+		difference := math.Abs(f2 - 2*fc - ratio*(f3-2*fc))
+		if difference > largest {
+			split, largest = i, difference
+		}
+	}
+
+	for i := 0; i < r.d; i++ {
+		for j := i + 1; j < r.d; j++ {
+			for _, si := range []float64{-1, 1} {
+				for _, sj := range []float64{-1, 1} {
+					x[i] = center[i] + si*genzMalikLambda4*half[i]
+					x[j] = center[j] + sj*genzMalikLambda4*half[j]
+					sums[3] += f(x)
+				}
+			}
+			x[i], x[j] = center[i], center[j]
+		}
+	}
+
+	for corner := 0; corner < 1<<r.d; corner++ {
+		for i := 0; i < r.d; i++ {
+			if corner&(1<<i) != 0 {
+				x[i] = center[i] + genzMalikLambda5*half[i]
+			} else {
+				x[i] = center[i] - genzMalikLambda5*half[i]
+			}
+		}
+		sums[4] += f(x)
+	}
+
+	volume := 1.0
+	for _, h := range half {
+		volume *= 2 * h
+	}
+	var i7, i5 float64
+	for k := range sums {
+		i7 += r.w7[k] * sums[k]
+		i5 += r.w5[k] * sums[k]
+	}
+	return genzMalikBox{
+		center: append([]float64(nil), center...),
+		half:   append([]float64(nil), half...),
+		value:  i7 * volume,
+		err:    math.Abs(i7-i5) * volume,
+		split:  split,
+	}
+}
+
+type genzMalikBox struct {
+	center, half []float64
+	value, err   float64
+	split        int
+}
+
+// genzMalikQueue is a max-heap of sub-boxes ordered by their estimated error.
+type genzMalikQueue []genzMalikBox
+
+func (q genzMalikQueue) Len() int           { return len(q) }
+func (q genzMalikQueue) Less(i, j int) bool { return q[i].err > q[j].err }
+func (q genzMalikQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *genzMalikQueue) Push(x any)        { *q = append(*q, x.(genzMalikBox)) }
+func (q *genzMalikQueue) Pop() any {
+	old := *q
+	n := len(old)
+	item := old[n-1]
+	*q = old[:n-1]
+	return item
+}
+
+func (q genzMalikQueue) sum() float64 {
+	out := 0.0
+	for _, box := range q {
+		out += box.value
+	}
+	return out
+}
@@ -0,0 +1,142 @@
+package integration
+
+import (
+	"github.com/rocas777/kairos"
+	"math"
+)
+
+// Improper provides a method to calculate [improper integrals] over infinite and semi-infinite intervals.
+// Infinite bounds are detected automatically, and the interval is mapped onto a finite one through a change of variables:
+//   - [a, +Inf) uses x = a + t/(1-t), with t in [0, 1)
+//   - (-Inf, b] uses x = b - (1-t)/t, with t in (0, 1]
+//   - (-Inf, +Inf) uses x = t/(1-t²), with t in (-1, 1)
+//
+// The transformed integrand is then integrated with 'Integrator'. At the endpoints that correspond to an infinite x,
+// the transformed integrand is taken as 0, which assumes 'f' decays fast enough for the integral to converge.
+// Finite intervals are handed to 'Integrator' untouched.
+//
+// If 'Integrator' is not specified, it defaults to a [GaussKronrod] with its default settings, which never evaluates the endpoints.
+//
+// [improper integrals]: https://en.wikipedia.org/wiki/Improper_integral
+type Improper struct {
+	Integrator Integrator
+	cycles     uint
+}
+
+// NewImproper creates and returns a pointer to a new [Improper] instance that integrates the transformed function with 'integrator'.
+//
+// If 'integrator' is nil, it defaults to a [GaussKronrod] with its default settings.
+func NewImproper(integrator Integrator) *Improper {
+	return &Improper{Integrator: integrator}
+}
+
+// Cycles returns the number of evaluations of the integrand made by the last integration.
+func (s *Improper) Cycles() uint {
+	return s.cycles
+}
+
+// DefiniteIntegral calculates the definite integral of the given function 'f' over [a, b], where 'a' may be -Inf and 'b' may be +Inf.
+//
+// The function 'f' represents the integrand, and 'a' and 'b' define the integration interval.
+// The result is returned as a float64 representing the calculated definite integral.
+func (s *Improper) DefiniteIntegral(f func(x float64) float64, a, b float64) float64 {
+	s.handleInput()
+	g, ta, tb := s.transform(f, a, b)
+	return s.Integrator.DefiniteIntegral(g, ta, tb)
+}
+
+// DefiniteIntegralResult works like [Improper.DefiniteIntegral] but returns a [Result] together with an error,
+// when 'Integrator' provides them, like [GaussKronrod] does. Otherwise the error estimate is reported as NaN.
+func (s *Improper) DefiniteIntegralResult(f func(x float64) float64, a, b float64) (Result, error) {
+	s.handleInput()
+	g, ta, tb := s.transform(f, a, b)
+	if integrator, ok := s.Integrator.(interface {
+		DefiniteIntegralResult(f func(x float64) float64, a, b float64) (Result, error)
+	}); ok {
+		r, err := integrator.DefiniteIntegralResult(g, ta, tb)
+		r.Evaluations = s.cycles
+		return r, err
+	}
+	value := s.Integrator.DefiniteIntegral(g, ta, tb)
+	return Result{Value: value, ErrorEstimate: math.NaN(), Evaluations: s.cycles}, nil
+}
+
+// AntiDerivative calculates the approximate antiderivative of the given function 'f'.
+// It samples the antiderivative at 'samples' points within the finite interval [a, b].
+// The antiderivative is the integral from 0 to each sampled point 'x'. It integrates from 0 to 'a' once, then only the panel between
+// each sample and the previous one, so the cost grows linearly with the number of samples.
+// [Improper.Cycles] then reports the total number of evaluations of 'f'.
+//
+// If 'samples' is less than 2, it defaults to 2.
+//
+// The function 'f' represents the integrand, and 'a' and 'b' define the integration interval.
+// The result is returned as a slice of [kairos.Pair] representing the sampled points and their corresponding antiderivative values.
+func (s *Improper) AntiDerivative(f func(x float64) float64, a, b float64, samples uint) []kairos.Pair {
+	s.handleInput()
+	if samples < 2 {
+		samples = 2
+	}
+	out, evaluations := antiDerivative(s.DefiniteIntegral, s.DefiniteIntegral, f, a, b, samples)
+	s.cycles = evaluations
+	return out
+}
+
+// transform returns the integrand and the finite interval to integrate in place of 'f' over [a, b].
+// Every evaluation of the returned integrand is counted in the cycles.
+func (s *Improper) transform(f func(x float64) float64, a, b float64) (g func(t float64) float64, ta, tb float64) {
+	s.cycles = 0
+	counted := func(x float64) float64 {
+		s.cycles++
+		return f(x)
+	}
+	// a change of variables with a jacobian that blows up where x does
+	change := func(x, dx func(t float64) float64) func(t float64) float64 {
+		return func(t float64) float64 {
+			xt := x(t)
+			if math.IsInf(xt, 0) || math.IsNaN(xt) {
+				return 0
+			}
+			return counted(xt) * dx(t)
+		}
+	}
+
+	sign := 1.0
+	if a > b {
+		a, b = b, a
+		sign = -1
+	}
+	switch {
+	case math.IsInf(a, -1) && math.IsInf(b, 1):
+		g = change(
+			func(t float64) float64 { return t / (1 - t*t) },
+			func(t float64) float64 { return (1 + t*t) / ((1 - t*t) * (1 - t*t)) },
+		)
+		ta, tb = -1, 1
+	case math.IsInf(b, 1):
+		g = change(
+			func(t float64) float64 { return a + t/(1-t) },
+			func(t float64) float64 { return 1 / ((1 - t) * (1 - t)) },
+		)
+		ta, tb = 0, 1
+	case math.IsInf(a, -1):
+		g = change(
+			func(t float64) float64 { return b - (1-t)/t },
+			func(t float64) float64 { return 1 / (t * t) },
+		)
+		ta, tb = 0, 1
+	default:
+		g = counted
+		ta, tb = a, b
+	}
+	if sign < 0 {
+		inner := g
+		g = func(t float64) float64 { return -inner(t) }
+	}
+	return g, ta, tb
+}
+
+func (s *Improper) handleInput() {
+	if s.Integrator == nil {
+		s.Integrator = &GaussKronrod{}
+	}
+}
@@ -1,8 +1,13 @@
 package integration_test
 
 import (
+	"errors"
+	"fmt"
+	"github.com/rocas777/kairos"
+	"github.com/rocas777/kairos/differentiation"
 	"github.com/rocas777/kairos/integration"
 	"math"
+	"math/cmplx"
 	"testing"
 )
 
@@ -144,3 +149,720 @@ func TestSimpson_adaptive(t *testing.T) {
 		})
 	}
 }
+
+func TestGaussLegendre(t *testing.T) {
+	a := 0.0
+	b := 10.0
+
+	tests := []struct {
+		name string
+		f    func(x float64) float64
+		a    float64
+		b    float64
+		sol  func() float64
+	}{
+		{"simple", simple, a, b, simpleSol},
+		{"smooth", smooth, a, b, smoothSol},
+		{"oscillatory", oscillatory, a, b, oscillatorySol},
+		{"exponential", exponential, a, b, exponentialSol},
+		{"singularity", singularity, a + 1, b, singularitySol},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			check(integration.NewGaussLegendre(10).DefiniteIntegral(test.f, test.a, test.b), test.sol(), t)
+		})
+		t.Run(test.name+"_composite", func(t *testing.T) {
+			check(integration.NewCompositeGaussLegendre(5, 4).DefiniteIntegral(test.f, test.a, test.b), test.sol(), t)
+		})
+	}
+}
+
+func TestGaussLegendrePrecision(t *testing.T) {
+	// an n point rule is exact for polynomials of degree 2n-1
+	f := func(x float64) float64 { return math.Pow(x, 9) }
+	got := integration.NewGaussLegendre(5).DefiniteIntegral(f, 0, 2)
+	if math.Abs(got-102.4) > 1e-10 {
+		t.Fatalf("Got: %.15f, wanted: 102.4", got)
+	}
+}
+
+func TestGaussKronrod(t *testing.T) {
+	a := 0.0
+	b := 10.0
+
+	tests := []struct {
+		name string
+		f    func(x float64) float64
+		a    float64
+		b    float64
+		sol  func() float64
+	}{
+		{"simple", simple, a, b, simpleSol},
+		{"smooth", smooth, a, b, smoothSol},
+		{"oscillatory", oscillatory, a, b, oscillatorySol},
+		{"exponential", exponential, a, b, exponentialSol},
+		{"singularity", singularity, a + 1, b, singularitySol},
+	}
+	for _, test := range tests {
+		for _, points := range []uint{15, 21} {
+			t.Run(fmt.Sprintf("%s_%d", test.name, points), func(t *testing.T) {
+				m := integration.GaussKronrod{AbsTolerance: 1e-10, Points: points}
+				r, err := m.DefiniteIntegralResult(test.f, test.a, test.b)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if math.Abs(r.Value-test.sol()) > 1e-9 || r.ErrorEstimate > 1e-10 {
+					t.Fatalf("Got: %.12f ± %g, wanted: %.12f", r.Value, r.ErrorEstimate, test.sol())
+				}
+			})
+		}
+	}
+}
+
+func TestGaussKronrodEndpointSingularity(t *testing.T) {
+	tests := []struct {
+		name string
+		f    func(x float64) float64
+		sol  float64
+	}{
+		{"inverse sqrt", func(x float64) float64 { return 1 / math.Sqrt(x) }, 2},
+		{"log", math.Log, -1},
+	}
+	for _, test := range tests {
+		for _, extrapolate := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s_%t", test.name, extrapolate), func(t *testing.T) {
+				m := integration.GaussKronrod{AbsTolerance: 1e-8, Extrapolate: extrapolate}
+				r, err := m.DefiniteIntegralResult(test.f, 0, 1)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if math.Abs(r.Value-test.sol) > 1e-7 {
+					t.Fatalf("Got: %.12f ± %g, wanted: %.12f", r.Value, r.ErrorEstimate, test.sol)
+				}
+			})
+		}
+	}
+}
+
+func TestGaussKronrodBudget(t *testing.T) {
+	m := integration.GaussKronrod{AbsTolerance: 1e-14, MaxEvaluations: 100}
+	r, err := m.DefiniteIntegralResult(func(x float64) float64 { return 1 / math.Sqrt(x) }, 0, 1)
+	if !errors.Is(err, integration.ErrMaxEvaluations) || r.Evaluations > 100 {
+		t.Fatalf("Got: %v after %d evaluations, wanted: %v", err, r.Evaluations, integration.ErrMaxEvaluations)
+	}
+}
+
+func TestRomberg(t *testing.T) {
+	a := 0.0
+	b := 10.0
+
+	tests := []struct {
+		name string
+		f    func(x float64) float64
+		a    float64
+		b    float64
+		sol  func() float64
+	}{
+		{"simple", simple, a, b, simpleSol},
+		{"smooth", smooth, a, b, smoothSol},
+		{"oscillatory", oscillatory, a, b, oscillatorySol},
+		{"exponential", exponential, a, b, exponentialSol},
+		{"singularity", singularity, a + 1, b, singularitySol},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			check(integration.NewRomberg(0.0001).DefiniteIntegral(test.f, test.a, test.b), test.sol(), t)
+		})
+	}
+}
+
+func TestRombergTableau(t *testing.T) {
+	m := integration.NewRomberg(1e-10)
+	got := m.DefiniteIntegral(smooth, 0, 10)
+	tableau := m.Tableau()
+	if uint(len(tableau)) != m.Cycles() || len(tableau[len(tableau)-1]) != len(tableau) {
+		t.Fatalf("Got: tableau with %d rows, wanted: %d", len(tableau), m.Cycles())
+	}
+	if math.Abs(got-smoothSol()) > 1e-9 {
+		t.Fatalf("Got: %.12f, wanted: %.12f", got, smoothSol())
+	}
+	// after an antiderivative, both describe the integration of the last panel
+	m.AntiDerivative(math.Cos, 1, 2, 11)
+	tableau = m.Tableau()
+	if uint(len(tableau)) != m.Cycles() || len(tableau[len(tableau)-1]) != len(tableau) {
+		t.Fatalf("Got: tableau with %d rows after AntiDerivative, wanted: %d", len(tableau), m.Cycles())
+	}
+	if last := tableau[len(tableau)-1]; math.Abs(last[len(last)-1]-(math.Sin(2)-math.Sin(1.9))) > 1e-9 {
+		t.Fatalf("Got: %.12f for the last panel, wanted: %.12f", last[len(last)-1], math.Sin(2)-math.Sin(1.9))
+	}
+}
+
+func TestImproper(t *testing.T) {
+	inf := math.Inf(1)
+
+	tests := []struct {
+		name string
+		f    func(x float64) float64
+		a    float64
+		b    float64
+		sol  float64
+	}{
+		{"gaussian", smooth, -inf, inf, math.Sqrt(math.Pi)},
+		{"upper", func(x float64) float64 { return math.Exp(-x) }, 0, inf, 1},
+		{"lower", func(x float64) float64 { return math.Exp(x) }, -inf, 0, 1},
+		{"power", func(x float64) float64 { return 1 / (x * x) }, 1, inf, 1},
+		{"reversed", func(x float64) float64 { return math.Exp(-x) }, inf, 0, -1},
+		{"finite", smooth, 0, 10, smoothSol()},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r, err := integration.NewImproper(nil).DefiniteIntegralResult(test.f, test.a, test.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(r.Value-test.sol) > 1e-7 {
+				t.Fatalf("Got: %.12f ± %g, wanted: %.12f", r.Value, r.ErrorEstimate, test.sol)
+			}
+		})
+		t.Run(test.name+"_simpson", func(t *testing.T) {
+			check(integration.NewImproper(integration.NewSimpson_1_3(100)).DefiniteIntegral(test.f, test.a, test.b), test.sol, t)
+		})
+	}
+}
+
+func TestTanhSinh(t *testing.T) {
+	a := 0.0
+	b := 10.0
+
+	tests := []struct {
+		name string
+		f    func(x float64) float64
+		a    float64
+		b    float64
+		sol  func() float64
+	}{
+		{"simple", simple, a, b, simpleSol},
+		{"smooth", smooth, a, b, smoothSol},
+		{"oscillatory", oscillatory, a, b, oscillatorySol},
+		{"exponential", exponential, a, b, exponentialSol},
+		{"singularity", singularity, a + 1, b, singularitySol},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			check(integration.NewTanhSinh(1e-8).DefiniteIntegral(test.f, test.a, test.b), test.sol(), t)
+		})
+	}
+}
+
+func TestTanhSinhEndpointSingularity(t *testing.T) {
+	tests := []struct {
+		name string
+		f    func(x float64) float64
+		a    float64
+		b    float64
+		sol  float64
+	}{
+		{"inverse sqrt", func(x float64) float64 { return 1 / math.Sqrt(x) }, 0, 1, 2},
+		{"log", math.Log, 0, 1, -1},
+		{"right endpoint", func(x float64) float64 { return 1 / math.Sqrt(1-x) }, 0, 1, 2},
+		{"both endpoints", func(x float64) float64 { return 1 / math.Sqrt(1-x*x) }, -1, 1, math.Pi},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r, err := integration.NewTanhSinh(1e-8).DefiniteIntegralResult(test.f, test.a, test.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(r.Value-test.sol) > 1e-6 {
+				t.Fatalf("Got: %.12f ± %g, wanted: %.12f", r.Value, r.ErrorEstimate, test.sol)
+			}
+		})
+	}
+}
+
+func TestCubature(t *testing.T) {
+	gaussian := func(x []float64) float64 {
+		out := 0.0
+		for _, xi := range x {
+			out -= xi * xi
+		}
+		return math.Exp(out)
+	}
+	product := func(x []float64) float64 {
+		out := 1.0
+		for _, xi := range x {
+			out *= xi
+		}
+		return out
+	}
+	squares := func(x []float64) float64 {
+		out := 0.0
+		for _, xi := range x {
+			out += xi * xi
+		}
+		return out
+	}
+
+	tests := []struct {
+		name string
+		f    func(x []float64) float64
+		a    []float64
+		b    []float64
+		sol  float64
+	}{
+		{"product2", product, []float64{0, 0}, []float64{1, 2}, 1},
+		{"gaussian3", gaussian, []float64{0, 0, 0}, []float64{1, 1, 1}, math.Pow(math.Sqrt(math.Pi)/2*math.Erf(1), 3)},
+		{"squares5", squares, []float64{0, 0, 0, 0, 0}, []float64{1, 1, 1, 1, 1}, 5.0 / 3},
+	}
+	for _, test := range tests {
+		t.Run(test.name+"_trapezoid", func(t *testing.T) {
+			check(integration.NewTrapezoidCubature(6).DefiniteIntegral(test.f, test.a, test.b), test.sol, t)
+		})
+		t.Run(test.name+"_simpson", func(t *testing.T) {
+			check(integration.NewSimpson_1_3Cubature(3).DefiniteIntegral(test.f, test.a, test.b), test.sol, t)
+		})
+		t.Run(test.name+"_genz_malik", func(t *testing.T) {
+			r, err := integration.NewGenzMalik(1e-8, 0).DefiniteIntegralResult(test.f, test.a, test.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(r.Value-test.sol) > 1e-7 {
+				t.Fatalf("Got: %.12f ± %g, wanted: %.12f", r.Value, r.ErrorEstimate, test.sol)
+			}
+		})
+	}
+}
+
+func TestMonteCarlo(t *testing.T) {
+	product := func(x []float64) float64 {
+		out := 1.0
+		for _, xi := range x {
+			out *= 2 * xi
+		}
+		return out
+	}
+	ones := func(d int, v float64) []float64 {
+		out := make([]float64, d)
+		for i := range out {
+			out[i] = v
+		}
+		return out
+	}
+
+	for _, sampling := range []integration.Sampling{integration.PlainSampling, integration.StratifiedSampling, integration.SobolSampling, integration.HaltonSampling} {
+		t.Run(fmt.Sprintf("sin_%d", sampling), func(t *testing.T) {
+			m := integration.NewMonteCarlo(20000, sampling, 7)
+			r, err := m.DefiniteIntegralResult(math.Sin, 0, math.Pi)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(r.Value-2) > 5*r.ErrorEstimate+1e-9 || r.ErrorEstimate > 0.05 {
+				t.Fatalf("Got: %f ± %g, wanted: 2", r.Value, r.ErrorEstimate)
+			}
+		})
+		t.Run(fmt.Sprintf("product8_%d", sampling), func(t *testing.T) {
+			m := integration.MonteCarlo{Samples: 50000, Sampling: sampling, Seed: 3, Workers: 4}
+			r, err := m.BoxIntegralResult(product, ones(8, 0), ones(8, 1))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(r.Value-1) > 5*r.ErrorEstimate+1e-9 {
+				t.Fatalf("Got: %f ± %g, wanted: 1", r.Value, r.ErrorEstimate)
+			}
+			serial := integration.MonteCarlo{Samples: 50000, Sampling: sampling, Seed: 3, Workers: 1}
+			if got := serial.BoxIntegral(product, ones(8, 0), ones(8, 1)); got != r.Value {
+				t.Fatalf("Got: %.15f with 1 worker and %.15f with 4 workers", got, r.Value)
+			}
+		})
+	}
+}
+
+func TestSampledIntegral(t *testing.T) {
+	// unevenly spaced samples of sin(x) over [0, 3]
+	var data []kairos.Pair
+	for i := 0; i <= 40; i++ {
+		x := 3 * math.Pow(float64(i)/40, 1.5)
+		data = append(data, kairos.Pair{X: x, Y: math.Sin(x)})
+	}
+
+	tests := []struct {
+		name      string
+		data      []kairos.Pair
+		rule      integration.SampleRule
+		tolerance float64
+	}{
+		{"trapezoid", data, integration.TrapezoidSamples, 1e-2},
+		{"simpson", data, integration.SimpsonSamples, 1e-4},
+		{"simpson_odd", data[:len(data)-1], integration.SimpsonSamples, 1e-4},
+		{"spline", data, integration.SplineSamples, 1e-3},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			want := 1 - math.Cos(test.data[len(test.data)-1].X)
+			cumulative := integration.CumulativeSampledIntegral(test.data, test.rule)
+			got := integration.SampledIntegral(test.data, test.rule)
+			if math.Abs(got-want) > test.tolerance || got != cumulative[len(cumulative)-1].Y {
+				t.Fatalf("Got: %.10f, wanted: %.10f", got, want)
+			}
+			for _, p := range cumulative {
+				if math.Abs(p.Y-(1-math.Cos(p.X))) > test.tolerance {
+					t.Fatalf("Got: %.10f at %f, wanted: %.10f", p.Y, p.X, 1-math.Cos(p.X))
+				}
+			}
+		})
+	}
+
+	t.Run("derivative", func(t *testing.T) {
+		derivative := differentiation.NewSymmetric(0.001).RangeDerivative(func(x float64) float64 { return x * x }, 0, 2, 11)
+		check(integration.SampledIntegral(derivative, integration.SimpsonSamples), 4, t)
+	})
+}
+
+func TestCumulativeIntegral(t *testing.T) {
+	type cumulative interface {
+		integration.Integrator
+		CumulativeIntegral(f func(x float64) float64, a, b float64, samples uint, lower, constant float64) []kairos.Pair
+	}
+	tests := []struct {
+		name       string
+		integrator cumulative
+		tolerance  float64
+	}{
+		{"trapezoid", integration.NewTrapezoid(100), 1e-5},
+		{"simpson_1_3", integration.NewSimpson_1_3(100), 1e-8},
+		{"simpson_3_8", integration.NewSimpson_3_8(100), 1e-8},
+		{"simpson_adaptive", integration.NewSimpsonAdaptive(1e-8), 1e-7},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			const samples = 10001
+			got := test.integrator.CumulativeIntegral(math.Cos, 1, 4, samples, 0, 2)
+			if len(got) != samples {
+				t.Fatalf("Got %d samples, wanted %d", len(got), samples)
+			}
+			for _, p := range got {
+				if math.Abs(p.Y-(2+math.Sin(p.X))) > test.tolerance {
+					t.Fatalf("Got: %.10f at %f, wanted: %.10f", p.Y, p.X, 2+math.Sin(p.X))
+				}
+			}
+			// every panel should cost a bounded number of evaluations
+			if test.integrator.Cycles() > 20*samples {
+				t.Fatalf("Used %d evaluations for %d samples", test.integrator.Cycles(), samples)
+			}
+
+			antiDerivative := test.integrator.AntiDerivative(math.Cos, 1, 4, 11)
+			for _, p := range antiDerivative {
+				check(p.Y, math.Sin(p.X), t)
+			}
+		})
+	}
+
+	t.Run("evaluations", func(t *testing.T) {
+		trapezoid := integration.NewTrapezoid(10)
+		trapezoid.CumulativeIntegral(simple, 0, 10, 101, 0, 0)
+		if trapezoid.Cycles() != 101 {
+			t.Fatalf("Got %d evaluations, wanted 101", trapezoid.Cycles())
+		}
+	})
+}
+
+func TestAntiDerivative(t *testing.T) {
+	tests := []struct {
+		name       string
+		integrator integration.Integrator
+		tolerance  float64
+	}{
+		{"gauss_legendre", integration.NewGaussLegendre(5), 1e-8},
+		{"gauss_kronrod", integration.NewGaussKronrod(1e-8, 0), 1e-8},
+		{"romberg", integration.NewRomberg(1e-6), 1e-7},
+		{"improper", integration.NewImproper(nil), 1e-8},
+		{"tanh_sinh", integration.NewTanhSinh(1e-8), 1e-8},
+		{"monte_carlo", integration.NewMonteCarlo(10000, integration.SobolSampling, 1), 1e-3},
+		{"clenshaw_curtis", integration.NewAdaptiveClenshawCurtis(1e-8), 1e-8},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			const samples = 1001
+			got := test.integrator.AntiDerivative(math.Cos, 1, 4, samples)
+			if len(got) != samples {
+				t.Fatalf("Got %d samples, wanted %d", len(got), samples)
+			}
+			for _, p := range got {
+				if math.Abs(p.Y-math.Sin(p.X)) > test.tolerance {
+					t.Fatalf("Got: %.10f at %f, wanted: %.10f", p.Y, p.X, math.Sin(p.X))
+				}
+			}
+			// every panel should cost a bounded number of evaluations
+			if test.integrator.Cycles() > 100*samples {
+				t.Fatalf("Used %d evaluations for %d samples", test.integrator.Cycles(), samples)
+			}
+		})
+	}
+}
+
+func TestOscillatory(t *testing.T) {
+	// ∫ e^x·e^{iωx} over [0, 1] is (e^{1+iω} - 1) / (1+iω)
+	exact := func(omega float64, oscillator integration.Oscillator) float64 {
+		z := (cmplx.Exp(complex(1, omega)) - 1) / complex(1, omega)
+		if oscillator == integration.CosineOscillator {
+			return real(z)
+		}
+		return imag(z)
+	}
+	tests := []struct {
+		name       string
+		integrator func(omega float64, oscillator integration.Oscillator) integration.Integrator
+		tolerance  float64
+	}{
+		{"filon", func(omega float64, oscillator integration.Oscillator) integration.Integrator {
+			return integration.NewFilon(omega, oscillator, 10)
+		}, 1e-5},
+		{"levin", func(omega float64, oscillator integration.Oscillator) integration.Integrator {
+			return integration.NewLevin(omega, nil, oscillator)
+		}, 1e-10},
+	}
+	for _, test := range tests {
+		for _, omega := range []float64{10, 100, 1000, 10000} {
+			for _, oscillator := range []integration.Oscillator{integration.SineOscillator, integration.CosineOscillator} {
+				t.Run(fmt.Sprintf("%s_%g_%d", test.name, omega, oscillator), func(t *testing.T) {
+					integrator := test.integrator(omega, oscillator)
+					got := integrator.DefiniteIntegral(math.Exp, 0, 1)
+					want := exact(omega, oscillator)
+					if math.Abs(got-want) > test.tolerance {
+						t.Fatalf("Got: %.12f, wanted: %.12f", got, want)
+					}
+				})
+			}
+		}
+	}
+
+	t.Run("simpson", func(t *testing.T) {
+		// the same number of evaluations is not enough for the Simpson 1/3 rule
+		f := func(x float64) float64 { return math.Exp(x) * math.Sin(1000*x) }
+		got := integration.NewSimpson_1_3(10).DefiniteIntegral(f, 0, 1)
+		if math.Abs(got-exact(1000, integration.SineOscillator)) < 1e-5 {
+			t.Fatalf("Simpson 1/3 rule should not resolve the oscillations")
+		}
+	})
+
+	t.Run("levin_phase", func(t *testing.T) {
+		// ∫ (2x+1)·cos(ω(x²+x)) over [0, 1] is sin(2ω)/ω
+		levin := integration.NewLevin(500, func(x float64) float64 { return x*x + x }, integration.CosineOscillator)
+		got := levin.DefiniteIntegral(func(x float64) float64 { return 2*x + 1 }, 0, 1)
+		if math.Abs(got-math.Sin(1000)/500) > 1e-10 {
+			t.Fatalf("Got: %.12f, wanted: %.12f", got, math.Sin(1000)/500)
+		}
+	})
+
+	t.Run("antiderivative", func(t *testing.T) {
+		// ∫ e^t·cos(ωt) from 0 to x is the real part of (e^{(1+iω)x} - 1) / (1+iω)
+		want := func(x float64) float64 {
+			return real((cmplx.Exp(complex(x, 100*x)) - 1) / complex(1, 100))
+		}
+		for _, test := range tests {
+			integrator := test.integrator(100, integration.CosineOscillator)
+			const samples = 101
+			for _, p := range integrator.AntiDerivative(math.Exp, 0.5, 1, samples) {
+				if math.Abs(p.Y-want(p.X)) > test.tolerance {
+					t.Fatalf("%s: Got: %.12f at %f, wanted: %.12f", test.name, p.Y, p.X, want(p.X))
+				}
+			}
+			// every panel should cost a bounded number of evaluations
+			if integrator.Cycles() > 100*samples {
+				t.Fatalf("%s: Used %d evaluations for %d samples", test.name, integrator.Cycles(), samples)
+			}
+		}
+	})
+
+	t.Run("filon_small_omega", func(t *testing.T) {
+		check(integration.NewFilon(1e-9, integration.CosineOscillator, 10).DefiniteIntegral(smooth, 0, 10), smoothSol(), t)
+	})
+}
+
+func TestPrincipalValue(t *testing.T) {
+	square := func(x float64) float64 { return x * x }
+	tests := []struct {
+		name       string
+		integrator integration.Integrator
+		f          func(x float64) float64
+		a, b, c    float64
+		want       float64
+		tolerance  float64
+	}{
+		{"constant", nil, func(x float64) float64 { return 1 }, -1, 2, 0, math.Ln2, 1e-10},
+		{"exponential", nil, math.Exp, -1, 1, 0, 2.114501750751457, 1e-9},
+		{"square", nil, square, 0, 1, 0.3, 0.5 + 0.3 + 0.09*math.Log(0.7/0.3), 1e-9},
+		{"reversed", nil, square, 1, 0, 0.3, -(0.5 + 0.3 + 0.09*math.Log(0.7/0.3)), 1e-9},
+		{"gauss_legendre", integration.NewGaussLegendre(10), math.Exp, -1, 1, 0, 2.114501750751457, 1e-9},
+		{"simpson_1_3", integration.NewSimpson_1_3(50), math.Exp, -1, 1, 0, 2.114501750751457, 1e-6},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			principalValue := integration.NewPrincipalValue(test.integrator)
+			got := principalValue.DefiniteIntegral(test.f, test.a, test.b, test.c)
+			if math.Abs(got-test.want) > test.tolerance {
+				t.Fatalf("Got: %.12f, wanted: %.12f", got, test.want)
+			}
+		})
+	}
+
+	t.Run("result", func(t *testing.T) {
+		r, err := integration.NewPrincipalValue(nil).DefiniteIntegralResult(math.Exp, -1, 1, 0)
+		if err != nil || r.ErrorEstimate > 1e-8 || r.Evaluations == 0 {
+			t.Fatalf("Got: %+v, %v", r, err)
+		}
+		_, err = integration.NewPrincipalValue(nil).DefiniteIntegralResult(func(x float64) float64 { return 1 / x }, -1, 1, 0)
+		if !errors.Is(err, integration.ErrNonFinite) {
+			t.Fatalf("Got error %v, wanted %v", err, integration.ErrNonFinite)
+		}
+	})
+}
+
+func TestGaussWeighted(t *testing.T) {
+	sqrtPi := math.Sqrt(math.Pi)
+	tests := []struct {
+		name string
+		got  func() float64
+		want float64
+	}{
+		{"hermite_square", func() float64 {
+			return integration.NewGaussHermite(5).WeightedIntegral(func(x float64) float64 { return x * x })
+		}, sqrtPi / 2},
+		{"hermite_exact_degree", func() float64 {
+			return integration.NewGaussHermite(5).WeightedIntegral(func(x float64) float64 { return math.Pow(x, 8) })
+		}, 105 * sqrtPi / 16},
+		{"hermite_normal_moment", func() float64 {
+			return integration.NewGaussHermite(10).Expectation(func(x float64) float64 { return x * x }, 1, 2)
+		}, 5},
+		{"hermite_normal_cos", func() float64 {
+			return integration.NewGaussHermite(20).Expectation(math.Cos, 0, 1)
+		}, math.Exp(-0.5)},
+		{"laguerre_cube", func() float64 {
+			return integration.NewGaussLaguerre(2, 0).WeightedIntegral(func(x float64) float64 { return x * x * x })
+		}, 6},
+		{"laguerre_generalized", func() float64 {
+			return integration.NewGaussLaguerre(3, 0.5).WeightedIntegral(func(x float64) float64 { return x })
+		}, math.Gamma(2.5)},
+		{"laguerre_exponential_mean", func() float64 {
+			return integration.NewGaussLaguerre(10, 0).Expectation(func(x float64) float64 { return x }, 2)
+		}, 0.5},
+		{"laguerre_gamma_variance", func() float64 {
+			// a gamma distribution with shape 3 and rate 2 has E[X²] = 3·4/4
+			return integration.NewGaussLaguerre(10, 2).Expectation(func(x float64) float64 { return x * x }, 2)
+		}, 3},
+		{"jacobi_legendre", func() float64 {
+			return integration.NewGaussJacobi(10, 0, 0).WeightedIntegral(smooth, 0, 10)
+		}, integration.NewGaussLegendre(10).DefiniteIntegral(smooth, 0, 10)},
+		{"jacobi_weight", func() float64 {
+			return integration.NewGaussJacobi(4, 0.5, -0.5).WeightedIntegral(func(x float64) float64 { return 1 }, -1, 1)
+		}, math.Pi},
+		{"jacobi_mapped", func() float64 {
+			// ∫ sqrt(x)·x over [0, 4] with the weight (x-0)^0.5
+			return integration.NewGaussJacobi(4, 0, 0.5).WeightedIntegral(func(x float64) float64 { return x }, 0, 4)
+		}, 2.0 / 5 * 32},
+		{"chebyshev_first", func() float64 {
+			return integration.NewGaussChebyshev(3, integration.ChebyshevFirstKind).WeightedIntegral(func(x float64) float64 { return x * x }, -1, 1)
+		}, math.Pi / 2},
+		{"chebyshev_second", func() float64 {
+			return integration.NewGaussChebyshev(3, integration.ChebyshevSecondKind).WeightedIntegral(func(x float64) float64 { return x * x }, -1, 1)
+		}, math.Pi / 8},
+		{"chebyshev_mapped", func() float64 {
+			return integration.NewGaussChebyshev(3, integration.ChebyshevFirstKind).WeightedIntegral(func(x float64) float64 { return 1 }, 0, 2)
+		}, math.Pi},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.got()
+			if math.Abs(got-test.want) > 1e-9*math.Max(1, math.Abs(test.want)) {
+				t.Fatalf("Got: %.12f, wanted: %.12f", got, test.want)
+			}
+		})
+	}
+
+	t.Run("rule", func(t *testing.T) {
+		for _, n := range []uint{1, 2, 7, 40, 100} {
+			nodes, weights := integration.NewGaussHermite(n).Rule()
+			sum := 0.0
+			for i := range nodes {
+				if i > 0 && nodes[i] <= nodes[i-1] {
+					t.Fatalf("Nodes of the %d point rule are not increasing: %v", n, nodes)
+				}
+				sum += weights[i]
+			}
+			if math.Abs(sum-sqrtPi) > 1e-12 {
+				t.Fatalf("Weights of the %d point rule add up to %.15f, wanted %.15f", n, sum, sqrtPi)
+			}
+		}
+	})
+}
+
+func TestClenshawCurtis(t *testing.T) {
+	a := 0.0
+	b := 10.0
+
+	tests := []struct {
+		name string
+		f    func(x float64) float64
+		a    float64
+		b    float64
+		sol  func() float64
+	}{
+		{"simple", simple, a, b, simpleSol},
+		{"smooth", smooth, a, b, smoothSol},
+		{"oscillatory", oscillatory, a, b, oscillatorySol},
+		{"exponential", exponential, a, b, exponentialSol},
+		{"singularity", singularity, a + 1, b, singularitySol},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			check(integration.NewAdaptiveClenshawCurtis(1e-8).DefiniteIntegral(test.f, test.a, test.b), test.sol(), t)
+		})
+	}
+}
+
+func TestClenshawCurtisNested(t *testing.T) {
+	clenshawCurtis := integration.NewAdaptiveClenshawCurtis(1e-12)
+	r, err := clenshawCurtis.DefiniteIntegralResult(math.Exp, 0, 1)
+	if err != nil || math.Abs(r.Value-(math.E-1)) > 1e-12 {
+		t.Fatalf("Got: %.15f ± %g, %v, wanted: %.15f", r.Value, r.ErrorEstimate, err, math.E-1)
+	}
+	// every order is a power of two times the default, and no point is evaluated twice
+	order := uint(len(clenshawCurtis.Coefficients()) - 1)
+	if r.Evaluations != order+1 || clenshawCurtis.Cycles() != r.Evaluations {
+		t.Fatalf("Used %d evaluations for order %d", r.Evaluations, order)
+	}
+
+	_, err = integration.NewAdaptiveClenshawCurtis(1e-12).DefiniteIntegralResult(func(x float64) float64 { return math.Abs(x - 0.3) }, 0, 1)
+	if !errors.Is(err, integration.ErrMaxEvaluations) {
+		t.Fatalf("Got error %v, wanted %v", err, integration.ErrMaxEvaluations)
+	}
+}
+
+func TestClenshawCurtisCoefficients(t *testing.T) {
+	// x² = (T0(x) + T2(x)) / 2 on [-1, 1], and the same polynomial in t = x - 1 on [0, 2]
+	tests := []struct {
+		name string
+		a, b float64
+		f    func(x float64) float64
+	}{
+		{"unit", -1, 1, func(x float64) float64 { return x * x }},
+		{"shifted", 0, 2, func(x float64) float64 { return (x - 1) * (x - 1) }},
+	}
+	for _, test := range tests {
+		// the coefficients of orders that are powers of 2 come from a fast Fourier transform, the others from a direct transform
+		for _, n := range []uint{4, 6} {
+			t.Run(fmt.Sprintf("%s_%d", test.name, n), func(t *testing.T) {
+				clenshawCurtis := integration.NewClenshawCurtis(n)
+				check(clenshawCurtis.DefiniteIntegral(test.f, test.a, test.b), 2.0/3, t)
+				want := make([]float64, n+1)
+				want[0], want[2] = 0.5, 0.5
+				got := clenshawCurtis.Coefficients()
+				for k := range want {
+					if math.Abs(got[k]-want[k]) > 1e-12 {
+						t.Fatalf("Got coefficients %v, wanted %v", got, want)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package integration
+
+import "github.com/rocas777/kairos"
+
+// Integrator is implemented by every method of this package that integrates a function over a finite interval [a, b].
+// It allows callers to choose, or fall back between, integration methods without depending on their concrete types.
+type Integrator interface {
+	DefiniteIntegral(f func(x float64) float64, a, b float64) float64
+	AntiDerivative(f func(x float64) float64, a, b float64, samples uint) []kairos.Pair
+	Cycles() uint
+}
+
+var (
+	_ Integrator = (*Trapezoid)(nil)
+	_ Integrator = (*Simpson_1_3)(nil)
+	_ Integrator = (*Simpson_3_8)(nil)
+	_ Integrator = (*SimpsonAdaptive)(nil)
+	_ Integrator = (*GaussLegendre)(nil)
+	_ Integrator = (*GaussKronrod)(nil)
+	_ Integrator = (*Romberg)(nil)
+	_ Integrator = (*Improper)(nil)
+	_ Integrator = (*TanhSinh)(nil)
+	_ Integrator = (*MonteCarlo)(nil)
+	_ Integrator = (*Filon)(nil)
+	_ Integrator = (*Levin)(nil)
+	_ Integrator = (*ClenshawCurtis)(nil)
+)
@@ -0,0 +1,433 @@
+package integration
+
+import (
+	"github.com/rocas777/kairos"
+	"math"
+	"math/rand"
+	"sync"
+)
+
+// Sampling selects how [MonteCarlo] chooses the points where the integrand is evaluated.
+type Sampling int
+
+const (
+	// PlainSampling draws independent uniformly distributed points.
+	PlainSampling Sampling = iota
+	// StratifiedSampling splits the box into equal cells and draws the same number of uniform points in each of them.
+	StratifiedSampling
+	// SobolSampling uses the Sobol low-discrepancy sequence, randomized with random shifts. It supports up to 21 dimensions.
+	SobolSampling
+	// HaltonSampling uses the Halton low-discrepancy sequence, randomized with random shifts.
+	HaltonSampling
+)
+
+// MonteCarlo provides a method to calculate the definite integral of a function, of one or several variables, using [Monte Carlo integration].
+// The integral is estimated as the volume of the domain times the average value of the function over 'Samples' points.
+// The points are chosen according to 'Sampling', either pseudo-randomly or from a quasi-random (low-discrepancy) sequence.
+//
+// Every run is reproducible: the random numbers are drawn from generators derived from 'Seed', and the work is split into batches with their
+// own generator, so the result does not depend on the number of 'Workers' goroutines used to evaluate the batches.
+// When 'Workers' is higher than 1, 'f' is called concurrently and must be safe for concurrent use.
+//
+// The standard error of the estimate is computed from the sample variance for [PlainSampling], from the variance within each cell for
+// [StratifiedSampling], and from the spread of independently shifted replicates of the sequence for [SobolSampling] and [HaltonSampling].
+//
+// If 'Samples' is not specified, it defaults to 10000. If 'Workers' is not specified, it defaults to 1.
+//
+// [Monte Carlo integration]: https://en.wikipedia.org/wiki/Monte_Carlo_integration
+type MonteCarlo struct {
+	Samples  uint
+	Sampling Sampling
+	Seed     int64
+	Workers  uint
+	cycles   uint
+}
+
+// NewMonteCarlo creates and returns a pointer to a new [MonteCarlo] instance drawing 'samples' points with the given 'sampling' and 'seed'.
+func NewMonteCarlo(samples uint, sampling Sampling, seed int64) *MonteCarlo {
+	return &MonteCarlo{Samples: samples, Sampling: sampling, Seed: seed}
+}
+
+// Cycles returns the number of evaluations of the integrand made by the last integration.
+func (m *MonteCarlo) Cycles() uint {
+	return m.cycles
+}
+
+// DefiniteIntegral calculates the definite integral of the given function 'f' over [a, b] using Monte Carlo integration.
+//
+// The function 'f' represents the integrand, and 'a' and 'b' define the integration interval.
+// The result is returned as a float64 representing the calculated definite integral.
+func (m *MonteCarlo) DefiniteIntegral(f func(x float64) float64, a, b float64) float64 {
+	r, _ := m.DefiniteIntegralResult(f, a, b)
+	return r.Value
+}
+
+// DefiniteIntegralResult works like [MonteCarlo.DefiniteIntegral] but returns a [Result] holding the integral, its standard error
+// and the number of evaluations. The error is [ErrNonFinite] if 'f' returns NaN or ±Inf.
+func (m *MonteCarlo) DefiniteIntegralResult(f func(x float64) float64, a, b float64) (Result, error) {
+	return m.BoxIntegralResult(func(x []float64) float64 { return f(x[0]) }, []float64{a}, []float64{b})
+}
+
+// BoxIntegral calculates the definite integral of the given function 'f' of several variables over the box [a, b] using Monte Carlo integration.
+// The slice passed to 'f' is reused between calls, so it must not be retained or modified.
+//
+// The function 'f' represents the integrand, and 'a' and 'b' hold the lower and upper bounds of each dimension.
+// The result is returned as a float64 representing the calculated definite integral.
+func (m *MonteCarlo) BoxIntegral(f func(x []float64) float64, a, b []float64) float64 {
+	r, _ := m.BoxIntegralResult(f, a, b)
+	return r.Value
+}
+
+// BoxIntegralResult works like [MonteCarlo.BoxIntegral] but returns a [Result] holding the integral, its standard error
+// and the number of evaluations. The error is [ErrNonFinite] if 'f' returns NaN or ±Inf.
+func (m *MonteCarlo) BoxIntegralResult(f func(x []float64) float64, a, b []float64) (Result, error) {
+	m.handleInput()
+	checkBox(a, b)
+	if m.Sampling == SobolSampling && len(a) > len(sobolDirections)+1 {
+		panic("MonteCarlo Sobol sampling supports up to 21 dimensions")
+	}
+
+	volume := 1.0
+	for i := range a {
+		volume *= b[i] - a[i]
+	}
+	var r Result
+	switch m.Sampling {
+	case StratifiedSampling:
+		r = m.stratified(f, a, b)
+	case SobolSampling, HaltonSampling:
+		r = m.quasiRandom(f, a, b)
+	default:
+		r = m.plain(f, a, b)
+	}
+	r.Value *= volume
+	r.ErrorEstimate *= math.Abs(volume)
+	m.cycles = r.Evaluations
+	if !finite(r.Value) {
+		return r, ErrNonFinite
+	}
+	return r, nil
+}
+
+// AntiDerivative calculates the approximate antiderivative of the given function 'f' using Monte Carlo integration.
+// It samples the antiderivative at 'samples' points within the interval [a, b].
+// The antiderivative is the integral from 0 to each sampled point 'x'. It integrates from 0 to 'a' once, then only the panel between
+// each sample and the previous one, with its share of 'Samples' and a seed of its own, so the cost grows linearly with the number of samples.
+// [MonteCarlo.Cycles] then reports the total number of evaluations of 'f'.
+//
+// If 'samples' is less than 2, it defaults to 2.
+//
+// The function 'f' represents the integrand, and 'a' and 'b' define the integration interval.
+// The result is returned as a slice of [kairos.Pair] representing the sampled points and their corresponding antiderivative values.
+func (m *MonteCarlo) AntiDerivative(f func(x float64) float64, a, b float64, samples uint) []kairos.Pair {
+	m.handleInput()
+	if samples < 2 {
+		samples = 2
+	}
+	panel := &MonteCarlo{Samples: m.Samples / (samples - 1), Sampling: m.Sampling, Seed: m.Seed, Workers: m.Workers}
+	if panel.Samples == 0 {
+		panel.Samples = 1
+	}
+	// a new seed for every panel keeps their errors independent
+	panelIntegral := func(f func(x float64) float64, a, b float64) float64 {
+		panel.Seed++
+		return panel.DefiniteIntegral(f, a, b)
+	}
+	out, evaluations := antiDerivative(m.DefiniteIntegral, panelIntegral, f, a, b, samples)
+	m.cycles = evaluations
+	return out
+}
+
+func (m *MonteCarlo) handleInput() {
+	if m.Samples == 0 {
+		m.Samples = 10000
+	}
+	if m.Workers == 0 {
+		m.Workers = 1
+	}
+}
+
+// monteCarloBatches is the number of independent batches, each with its own generator, the work is split into.
+const monteCarloBatches = 16
+
+// monteCarloBatch accumulates the values of the integrand, and their squares, over the points of a batch.
+type monteCarloBatch struct {
+	sum, sumSq float64
+	count      uint
+}
+
+// parallel calls job for every batch, spreading the batches over 'Workers' goroutines.
+// Each batch gets its own generator, seeded from 'Seed', so the results do not depend on the number of workers.
+func (m *MonteCarlo) parallel(job func(batch int, rng *rand.Rand)) {
+	master := rand.New(rand.NewSource(m.Seed))
+	seeds := make([]int64, monteCarloBatches)
+	for i := range seeds {
+		seeds[i] = master.Int63()
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < int(m.Workers); w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for batch := w; batch < monteCarloBatches; batch += int(m.Workers) {
+				job(batch, rand.New(rand.NewSource(seeds[batch])))
+			}
+		}(w)
+	}
+	wg.Wait()
+}
+
+// share returns the range of the 'total' items handled by a batch.
+func share(total uint, batch int) (from, to uint) {
+	return total * uint(batch) / monteCarloBatches, total * uint(batch+1) / monteCarloBatches
+}
+
+// plain averages the integrand over independent uniform points, the result is not scaled by the volume.
+func (m *MonteCarlo) plain(f func(x []float64) float64, a, b []float64) Result {
+	batches := make([]monteCarloBatch, monteCarloBatches)
+	m.parallel(func(batch int, rng *rand.Rand) {
+		x := make([]float64, len(a))
+		from, to := share(m.Samples, batch)
+		for n := from; n < to; n++ {
+			for i := range x {
+				x[i] = a[i] + rng.Float64()*(b[i]-a[i])
+			}
+			y := f(x)
+			batches[batch].sum += y
+			batches[batch].sumSq += y * y
+			batches[batch].count++
+		}
+	})
+
+	var total monteCarloBatch
+	for _, batch := range batches {
+		total.sum += batch.sum
+		total.sumSq += batch.sumSq
+		total.count += batch.count
+	}
+	n := float64(total.count)
+	mean := total.sum / n
+	variance := math.Max(total.sumSq/n-mean*mean, 0) * n / math.Max(n-1, 1)
+	return Result{Value: mean, ErrorEstimate: math.Sqrt(variance / n), Evaluations: total.count}
+}
+
+// stratified splits the box into k^d equal cells, with at least two points in each of them so that their variance can be estimated.
+// The result is not scaled by the volume.
+func (m *MonteCarlo) stratified(f func(x []float64) float64, a, b []float64) Result {
+	d := len(a)
+	k := uint(math.Floor(math.Pow(float64(m.Samples)/2, 1/float64(d))))
+	if k < 1 {
+		k = 1
+	}
+	for k > 1 && math.Pow(float64(k), float64(d)) > float64(m.Samples)/2 {
+		k--
+	}
+	cells := uint(math.Pow(float64(k), float64(d)))
+	perCell := m.Samples / cells
+	if perCell < 2 {
+		perCell = 2
+	}
+
+	type partial struct {
+		value, variance float64
+		count           uint
+	}
+	batches := make([]partial, monteCarloBatches)
+	m.parallel(func(batch int, rng *rand.Rand) {
+		x := make([]float64, d)
+		cell := make([]uint, d)
+		from, to := share(cells, batch)
+		for c := from; c < to; c++ {
+			index := c
+			for i := range cell {
+				cell[i] = index % k
+				index /= k
+			}
+			sum, sumSq := 0.0, 0.0
+			for n := uint(0); n < perCell; n++ {
+				for i := range x {
+					x[i] = a[i] + (float64(cell[i])+rng.Float64())/float64(k)*(b[i]-a[i])
+				}
+				y := f(x)
+				sum += y
+				sumSq += y * y
+			}
+			n := float64(perCell)
+			mean := sum / n
+			variance := math.Max(sumSq/n-mean*mean, 0) * n / (n - 1)
+			batches[batch].value += mean / float64(cells)
+			batches[batch].variance += variance / n / float64(cells*cells)
+			batches[batch].count += perCell
+		}
+	})
+
+	var out Result
+	variance := 0.0
+	for _, batch := range batches {
+		out.Value += batch.value
+		variance += batch.variance
+		out.Evaluations += batch.count
+	}
+	out.ErrorEstimate = math.Sqrt(variance)
+	return out
+}
+
+// quasiRandom averages the integrand over a low-discrepancy sequence. Each batch is an independent replicate of the sequence
+// shifted by a random vector (modulo 1), and the spread of the replicates gives the standard error.
+// The result is not scaled by the volume.
+func (m *MonteCarlo) quasiRandom(f func(x []float64) float64, a, b []float64) Result {
+	d := len(a)
+	perReplicate := m.Samples / monteCarloBatches
+	if perReplicate < 1 {
+		perReplicate = 1
+	}
+	estimates := make([]float64, monteCarloBatches)
+	m.parallel(func(batch int, rng *rand.Rand) {
+		shift := make([]float64, d)
+		for i := range shift {
+			shift[i] = rng.Float64()
+		}
+		var next func(u []float64)
+		if m.Sampling == SobolSampling {
+			next = newSobol(d)
+		} else {
+			next = newHalton(d)
+		}
+		u := make([]float64, d)
+		x := make([]float64, d)
+		sum := 0.0
+		for n := uint(0); n < perReplicate; n++ {
+			next(u)
+			for i := range x {
+				ui := u[i] + shift[i]
+				if ui >= 1 {
+					ui--
+				}
+				x[i] = a[i] + ui*(b[i]-a[i])
+			}
+			sum += f(x)
+		}
+		estimates[batch] = sum / float64(perReplicate)
+	})
+
+	mean := 0.0
+	for _, estimate := range estimates {
+		mean += estimate
+	}
+	mean /= monteCarloBatches
+	variance := 0.0
+	for _, estimate := range estimates {
+		variance += (estimate - mean) * (estimate - mean)
+	}
+	variance /= monteCarloBatches - 1
+	return Result{
+		Value:         mean,
+		ErrorEstimate: math.Sqrt(variance / monteCarloBatches),
+		Evaluations:   perReplicate * monteCarloBatches,
+	}
+}
+
+// newHalton returns a generator filling 'u' with the successive points of the Halton sequence in 'd' dimensions,
+// using the first 'd' prime numbers as bases.
+func newHalton(d int) func(u []float64) {
+	bases := make([]uint64, 0, d)
+	for candidate := uint64(2); len(bases) < d; candidate++ {
+		prime := true
+		for _, p := range bases {
+			if p*p > candidate {
+				break
+			}
+			if candidate%p == 0 {
+				prime = false
+				break
+			}
+		}
+		if prime {
+			bases = append(bases, candidate)
+		}
+	}
+
+	index := uint64(0)
+	return func(u []float64) {
+		index++
+		for i, base := range bases {
+			// radical inverse of the index in the given base
+			out, scale := 0.0, 1.0
+			for n := index; n > 0; n /= base {
+				scale /= float64(base)
+				out += float64(n%base) * scale
+			}
+			u[i] = out
+		}
+	}
+}
+
+// sobolDirections holds the degree 's', the coefficients 'a' of the primitive polynomial and the initial direction numbers 'm'
+// for the dimensions after the first one, as tabulated by Joe and Kuo.
+var sobolDirections = []struct {
+	s, a uint32
+	m    []uint32
+}{
+	{1, 0, []uint32{1}},
+	{2, 1, []uint32{1, 3}},
+	{3, 1, []uint32{1, 3, 1}},
+	{3, 2, []uint32{1, 1, 1}},
+	{4, 1, []uint32{1, 1, 3, 3}},
+	{4, 4, []uint32{1, 3, 5, 13}},
+	{5, 2, []uint32{1, 1, 5, 5, 17}},
+	{5, 4, []uint32{1, 1, 5, 5, 5}},
+	{5, 7, []uint32{1, 1, 7, 11, 19}},
+	{5, 11, []uint32{1, 1, 5, 1, 1}},
+	{5, 13, []uint32{1, 1, 1, 3, 11}},
+	{5, 14, []uint32{1, 3, 5, 5, 31}},
+	{6, 1, []uint32{1, 3, 3, 9, 7, 49}},
+	{6, 13, []uint32{1, 1, 1, 15, 21, 21}},
+	{6, 16, []uint32{1, 3, 1, 13, 27, 49}},
+	{6, 19, []uint32{1, 1, 1, 15, 7, 5}},
+	{6, 22, []uint32{1, 3, 1, 15, 13, 25}},
+	{6, 25, []uint32{1, 1, 5, 5, 19, 61}},
+	{7, 1, []uint32{1, 3, 7, 11, 23, 15, 103}},
+	{7, 4, []uint32{1, 3, 7, 13, 13, 15, 69}},
+}
+
+// newSobol returns a generator filling 'u' with the successive points of the Sobol sequence in 'd' dimensions,
+// using the Gray code construction of Antonov and Saleev.
+func newSobol(d int) func(u []float64) {
+	const bits = 32
+	directions := make([][bits]uint32, d)
+	for k := 0; k < bits; k++ {
+		directions[0][k] = 1 << (bits - 1 - k)
+	}
+	for i := 1; i < d; i++ {
+		p := sobolDirections[i-1]
+		v := &directions[i]
+		for k := uint32(0); k < bits; k++ {
+			if k < p.s {
+				v[k] = p.m[k] << (bits - 1 - k)
+				continue
+			}
+			v[k] = v[k-p.s] ^ (v[k-p.s] >> p.s)
+			for j := uint32(1); j < p.s; j++ {
+				if (p.a>>(p.s-1-j))&1 == 1 {
+					v[k] ^= v[k-j]
+				}
+			}
+		}
+	}
+
+	index := uint32(0)
+	x := make([]uint32, d)
+	return func(u []float64) {
+		// the bit that changes in the Gray code of the index is its rightmost zero
+		c := 0
+		for n := index; n&1 == 1; n >>= 1 {
+			c++
+		}
+		index++
+		for i := range x {
+			x[i] ^= directions[i][c]
+			u[i] = float64(x[i]) / (1 << bits)
+		}
+	}
+}
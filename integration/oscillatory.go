@@ -0,0 +1,327 @@
+package integration
+
+import (
+	"github.com/rocas777/kairos"
+	"math"
+	"math/cmplx"
+)
+
+// Oscillator selects the oscillating factor that multiplies the integrand in [Filon] and [Levin].
+type Oscillator int
+
+const (
+	// SineOscillator integrates f(x)·sin(ω·x), or f(x)·sin(ω·g(x)) for a [Levin] phase g.
+	SineOscillator Oscillator = iota
+	// CosineOscillator integrates f(x)·cos(ω·x), or f(x)·cos(ω·g(x)) for a [Levin] phase g.
+	CosineOscillator
+)
+
+// Filon provides a method to calculate the definite integral of f(x)·sin(ω·x) or f(x)·cos(ω·x), where ω is 'Omega', using [Filon's method].
+// Like the Simpson 1/3 rule, the interval is divided into N pieces and 'f' is interpolated by parabolas through each three successive points,
+// but the product of every parabola and the oscillating factor is then integrated exactly. Only 'f' has to be resolved by the grid, not the oscillations,
+// so the accuracy does not degrade as 'Omega' increases, while the [Simpson_1_3] rule needs N to grow with 'Omega'.
+// When ω·h is small, the rule turns into the Simpson 1/3 rule.
+//
+// 'Oscillator' selects the sine or the cosine factor, and defaults to [SineOscillator].
+//
+// If N is not specified, it defaults to 10. If the value is odd, a panic will be raised.
+//
+// [Filon's method]: https://mathworld.wolfram.com/FilonsIntegrationFormula.html
+type Filon struct {
+	Omega      float64
+	Oscillator Oscillator
+	N          uint
+	cycles     uint
+}
+
+// NewFilon creates and returns a pointer to a new [Filon] instance for the given 'omega' and 'oscillator'.
+// As with [NewSimpson_1_3], the interval is divided into 2n pieces.
+func NewFilon(omega float64, oscillator Oscillator, n uint) *Filon {
+	return &Filon{Omega: omega, Oscillator: oscillator, N: n * 2}
+}
+
+func (fl *Filon) Cycles() uint {
+	return fl.cycles
+}
+
+// DefiniteIntegral calculates the definite integral of f(x)·sin(ω·x) or f(x)·cos(ω·x) over [a, b] using Filon's method.
+// 'Cycles' reports the number of evaluations of 'f'.
+//
+// The function 'f' represents the non-oscillating part of the integrand, and 'a' and 'b' define the integration interval.
+// The result is returned as a float64 representing the calculated definite integral.
+func (fl *Filon) DefiniteIntegral(f func(x float64) float64, a, b float64) float64 {
+	fl.handleInput()
+
+	fl.cycles = 0
+	h := (b - a) / float64(fl.N)
+	alpha, beta, gamma := filonCoefficients(fl.Omega * h)
+	weight := math.Sin
+	if fl.Oscillator == CosineOscillator {
+		weight = math.Cos
+	}
+
+	var fa, fb, even, odd float64
+	for i := 0; i <= int(fl.N); i++ {
+		x := a + float64(i)*h
+		if i == int(fl.N) {
+			x = b
+		}
+		y := f(x)
+		fl.cycles++
+		switch {
+		case i == 0:
+			fa = y
+			even += y * weight(fl.Omega*x) / 2
+		case i == int(fl.N):
+			fb = y
+			even += y * weight(fl.Omega*x) / 2
+		case i%2 == 0:
+			even += y * weight(fl.Omega*x)
+		default:
+			odd += y * weight(fl.Omega*x)
+		}
+	}
+
+	endpoints := fa*math.Cos(fl.Omega*a) - fb*math.Cos(fl.Omega*b)
+	if fl.Oscillator == CosineOscillator {
+		endpoints = fb*math.Sin(fl.Omega*b) - fa*math.Sin(fl.Omega*a)
+	}
+	return h * (alpha*endpoints + beta*even + gamma*odd)
+}
+
+// AntiDerivative calculates the approximate antiderivative of f(x)·sin(ω·x) or f(x)·cos(ω·x) using Filon's method.
+// It samples the antiderivative at 'samples' points within the interval [a, b].
+// The antiderivative is the integral from 0 to each sampled point 'x'. It integrates from 0 to 'a' once, then only the panel between
+// each sample and the previous one, split into its share of the 'N' subintervals, so the cost grows linearly with the number of samples.
+// [Filon.Cycles] then reports the total number of evaluations of 'f'.
+//
+// If 'samples' is less than 2, it defaults to 2.
+//
+// The function 'f' represents the non-oscillating part of the integrand, and 'a' and 'b' define the integration interval.
+// The result is returned as a slice of [kairos.Pair] representing the sampled points and their corresponding antiderivative values.
+func (fl *Filon) AntiDerivative(f func(x float64) float64, a, b float64, samples uint) []kairos.Pair {
+	fl.handleInput()
+	if samples < 2 {
+		samples = 2
+	}
+	panel := &Filon{Omega: fl.Omega, Oscillator: fl.Oscillator, N: piecesPerPanel(fl.N, samples, 2)}
+	out, evaluations := antiDerivative(fl.DefiniteIntegral, panel.DefiniteIntegral, f, a, b, samples)
+	fl.cycles = evaluations
+	return out
+}
+
+func (fl *Filon) handleInput() {
+	if fl.N == 0 {
+		fl.N = 10
+	} else if fl.N%2 != 0 {
+		panic("Filon struct value of N should be even")
+	}
+}
+
+// filonCoefficients returns the weights α, β and γ of Filon's method for θ = ω·h.
+// For small θ the closed forms suffer from cancellation, so their Taylor series are used instead.
+func filonCoefficients(theta float64) (alpha, beta, gamma float64) {
+	if math.Abs(theta) < 1.0/6 {
+		t2 := theta * theta
+		alpha = theta * t2 * (2.0/45 - t2*(2.0/315-t2*2.0/4725))
+		beta = 2.0/3 + t2*(2.0/15-t2*(4.0/105-t2*2.0/567))
+		gamma = 4.0/3 - t2*(2.0/15-t2*(1.0/210-t2/11340))
+		return alpha, beta, gamma
+	}
+	sin, cos := math.Sincos(theta)
+	t3 := theta * theta * theta
+	alpha = (theta*theta + theta*sin*cos - 2*sin*sin) / t3
+	beta = 2 * (theta*(1+cos*cos) - 2*sin*cos) / t3
+	gamma = 4 * (sin - theta*cos) / t3
+	return alpha, beta, gamma
+}
+
+// Levin provides a method to calculate the definite integral of f(x)·sin(ω·g(x)) or f(x)·cos(ω·g(x)), where ω is 'Omega' and g is 'Phase',
+// using [Levin's method]. Instead of summing the oscillations, it looks for a slowly varying function p with (p·e^{iωg})' = f·e^{iωg},
+// so the integral is simply p(b)·e^{iωg(b)} - p(a)·e^{iωg(a)}. The differential equation p' + iω·g'·p = f is solved by collocation
+// on N+1 Chebyshev points, and g' is obtained by differentiating the Chebyshev interpolant of 'Phase', so only g itself is needed.
+// The accuracy does not degrade as 'Omega' increases; in fact, it usually improves.
+//
+// The interval can also be split into 'Panels' pieces, solving the collocation problem on each of them.
+// The method requires g' not to vanish on [a, b]; near such stationary points the collocation system becomes ill-conditioned.
+// For the same reason it loses accuracy when ω·(b-a)/Panels is small, where [Filon] or an ordinary rule should be preferred.
+//
+// 'Oscillator' selects the sine or the cosine factor, and defaults to [SineOscillator].
+//
+// If 'Phase' is not specified, it defaults to g(x) = x. If N is not specified, it defaults to 16. If 'Panels' is not specified, it defaults to 1.
+// If 'Omega' is 0, a panic will be raised.
+//
+// [Levin's method]: https://doi.org/10.1090/S0025-5718-1982-0645667-5
+type Levin struct {
+	Omega      float64
+	Phase      func(x float64) float64
+	Oscillator Oscillator
+	N          uint
+	Panels     uint
+	cycles     uint
+}
+
+// NewLevin creates and returns a pointer to a new [Levin] instance for the given 'omega', 'phase' and 'oscillator', with the default number of points.
+//
+// If 'omega' is 0, a panic is raised.
+func NewLevin(omega float64, phase func(x float64) float64, oscillator Oscillator) *Levin {
+	return &Levin{Omega: omega, Phase: phase, Oscillator: oscillator}
+}
+
+func (l *Levin) Cycles() uint {
+	return l.cycles
+}
+
+// DefiniteIntegral calculates the definite integral of f(x)·sin(ω·g(x)) or f(x)·cos(ω·g(x)) over [a, b] using Levin's method.
+// 'Cycles' reports the number of evaluations of 'f'.
+//
+// The function 'f' represents the non-oscillating part of the integrand, and 'a' and 'b' define the integration interval.
+// The result is returned as a float64 representing the calculated definite integral.
+func (l *Levin) DefiniteIntegral(f func(x float64) float64, a, b float64) float64 {
+	out := l.OscillatoryIntegral(f, a, b)
+	if l.Oscillator == CosineOscillator {
+		return real(out)
+	}
+	return imag(out)
+}
+
+// OscillatoryIntegral calculates the definite integral of f(x)·e^{iω·g(x)} over [a, b] using Levin's method.
+// Its real part is the integral with the cosine factor and its imaginary part the integral with the sine factor.
+func (l *Levin) OscillatoryIntegral(f func(x float64) float64, a, b float64) complex128 {
+	l.handleInput()
+
+	l.cycles = 0
+	nodes, d := chebyshevDifferentiation(l.N)
+	n := len(nodes)
+	h := (b - a) / float64(l.Panels)
+	x := make([]float64, n)
+	g := make([]float64, n)
+	var out complex128
+	for p := 0; p < int(l.Panels); p++ {
+		center := a + (float64(p)+0.5)*h
+		for j := range nodes {
+			x[j] = center + nodes[j]*h/2
+			g[j] = l.Phase(x[j])
+		}
+
+		system := make([][]complex128, n)
+		rhs := make([]complex128, n)
+		for i := range system {
+			system[i] = make([]complex128, n)
+			dg := 0.0
+			for j := range nodes {
+				system[i][j] = complex(d[i][j]*2/h, 0)
+				dg += d[i][j] * g[j] * 2 / h
+			}
+			system[i][i] += complex(0, l.Omega*dg)
+			rhs[i] = complex(f(x[i]), 0)
+			l.cycles++
+		}
+		q := solveComplex(system, rhs)
+
+		// the first node is the upper end of the panel and the last one the lower end
+		out += q[0]*cmplx.Exp(complex(0, l.Omega*g[0])) - q[n-1]*cmplx.Exp(complex(0, l.Omega*g[n-1]))
+	}
+	return out
+}
+
+// AntiDerivative calculates the approximate antiderivative of f(x)·sin(ω·g(x)) or f(x)·cos(ω·g(x)) using Levin's method.
+// It samples the antiderivative at 'samples' points within the interval [a, b].
+// The antiderivative is the integral from 0 to each sampled point 'x'. It integrates from 0 to 'a' once, then only the panel between
+// each sample and the previous one, split into its share of the 'Panels', so the cost grows linearly with the number of samples.
+// [Levin.Cycles] then reports the total number of evaluations of 'f'.
+//
+// If 'samples' is less than 2, it defaults to 2.
+//
+// The function 'f' represents the non-oscillating part of the integrand, and 'a' and 'b' define the integration interval.
+// The result is returned as a slice of [kairos.Pair] representing the sampled points and their corresponding antiderivative values.
+func (l *Levin) AntiDerivative(f func(x float64) float64, a, b float64, samples uint) []kairos.Pair {
+	l.handleInput()
+	if samples < 2 {
+		samples = 2
+	}
+	panel := &Levin{Omega: l.Omega, Phase: l.Phase, Oscillator: l.Oscillator, N: l.N, Panels: piecesPerPanel(l.Panels, samples, 1)}
+	out, evaluations := antiDerivative(l.DefiniteIntegral, panel.DefiniteIntegral, f, a, b, samples)
+	l.cycles = evaluations
+	return out
+}
+
+func (l *Levin) handleInput() {
+	if l.Omega == 0 {
+		panic("Levin struct value of Omega should not be 0")
+	}
+	if l.Phase == nil {
+		l.Phase = func(x float64) float64 { return x }
+	}
+	if l.N == 0 {
+		l.N = 16
+	}
+	if l.Panels == 0 {
+		l.Panels = 1
+	}
+}
+
+// chebyshevDifferentiation returns the n+1 Chebyshev-Lobatto points cos(jπ/n) of [-1, 1], in decreasing order,
+// and the matrix that maps the values of a polynomial at those points to the values of its derivative.
+func chebyshevDifferentiation(n uint) ([]float64, [][]float64) {
+	nodes := make([]float64, n+1)
+	for j := range nodes {
+		nodes[j] = math.Cos(math.Pi * float64(j) / float64(n))
+	}
+	d := make([][]float64, n+1)
+	for i := range d {
+		d[i] = make([]float64, n+1)
+		ci := 1.0
+		if i == 0 || i == int(n) {
+			ci = 2
+		}
+		for j := range d[i] {
+			if i == j {
+				continue
+			}
+			cj := 1.0
+			if j == 0 || j == int(n) {
+				cj = 2
+			}
+			sign := 1.0
+			if (i+j)%2 != 0 {
+				sign = -1
+			}
+			d[i][j] = sign * ci / (cj * (nodes[i] - nodes[j]))
+			// the diagonal makes every row sum to zero, which is more accurate than its closed form
+			d[i][i] -= d[i][j]
+		}
+	}
+	return nodes, d
+}
+
+// solveComplex solves the linear system m·x = rhs by Gaussian elimination with partial pivoting. Both 'm' and 'rhs' are overwritten.
+func solveComplex(m [][]complex128, rhs []complex128) []complex128 {
+	n := len(rhs)
+	for k := 0; k < n; k++ {
+		pivot := k
+		for i := k + 1; i < n; i++ {
+			if cmplx.Abs(m[i][k]) > cmplx.Abs(m[pivot][k]) {
+				pivot = i
+			}
+		}
+		m[k], m[pivot] = m[pivot], m[k]
+		rhs[k], rhs[pivot] = rhs[pivot], rhs[k]
+		for i := k + 1; i < n; i++ {
+			factor := m[i][k] / m[k][k]
+			for j := k; j < n; j++ {
+				m[i][j] -= factor * m[k][j]
+			}
+			rhs[i] -= factor * rhs[k]
+		}
+	}
+	x := make([]complex128, n)
+	for i := n - 1; i >= 0; i-- {
+		sum := rhs[i]
+		for j := i + 1; j < n; j++ {
+			sum -= m[i][j] * x[j]
+		}
+		x[i] = sum / m[i][i]
+	}
+	return x
+}
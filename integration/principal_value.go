@@ -0,0 +1,108 @@
+package integration
+
+import "math"
+
+// PrincipalValue provides a method to calculate the [Cauchy principal value] of the integral of f(x)/(x-c) over [a, b], where the pole 'c'
+// lies strictly inside the interval. Such integrals appear in Hilbert transforms and dispersion relations, and cannot be handled by the
+// other integrators of this package, which would evaluate the integrand close to the pole.
+//
+// The singularity is subtracted, so the principal value becomes
+//
+//	f(c)·ln((b-c)/(c-a)) + ∫ (f(x)-f(c))/(x-c) dx
+//
+// where the remaining integrand is smooth. It is integrated with 'Integrator' separately on [a, c] and [c, b], so that the pole is an endpoint
+// of both pieces. Rules that never evaluate the endpoints, like [GaussKronrod], are preferred; if the pole itself is evaluated,
+// the remaining integrand is replaced there by a central difference of 'f'.
+//
+// If 'Integrator' is not specified, it defaults to a [GaussKronrod] with its default settings.
+//
+// [Cauchy principal value]: https://en.wikipedia.org/wiki/Cauchy_principal_value
+type PrincipalValue struct {
+	Integrator Integrator
+	cycles     uint
+}
+
+// NewPrincipalValue creates and returns a pointer to a new [PrincipalValue] instance that integrates the remaining integrand with 'integrator'.
+//
+// If 'integrator' is nil, it defaults to a [GaussKronrod] with its default settings.
+func NewPrincipalValue(integrator Integrator) *PrincipalValue {
+	return &PrincipalValue{Integrator: integrator}
+}
+
+// Cycles returns the number of evaluations of 'f' made by the last integration.
+func (p *PrincipalValue) Cycles() uint {
+	return p.cycles
+}
+
+// DefiniteIntegral calculates the principal value of the integral of f(x)/(x-c) over [a, b].
+// If 'c' is not strictly between 'a' and 'b', a panic is raised.
+//
+// The function 'f' represents the numerator of the integrand, 'a' and 'b' define the integration interval and 'c' is the pole.
+// The result is returned as a float64 representing the calculated principal value.
+func (p *PrincipalValue) DefiniteIntegral(f func(x float64) float64, a, b, c float64) float64 {
+	r, _ := p.DefiniteIntegralResult(f, a, b, c)
+	return r.Value
+}
+
+// DefiniteIntegralResult works like [PrincipalValue.DefiniteIntegral] but returns a [Result] together with an error,
+// when 'Integrator' provides them, like [GaussKronrod] does. Otherwise the error estimate is reported as NaN.
+// The error is [ErrNonFinite] if 'f' is not finite at the pole.
+func (p *PrincipalValue) DefiniteIntegralResult(f func(x float64) float64, a, b, c float64) (Result, error) {
+	p.handleInput()
+	sign := 1.0
+	if a > b {
+		a, b = b, a
+		sign = -1
+	}
+	if !(c > a && c < b) {
+		panic("the pole of PrincipalValue should lie strictly inside the interval")
+	}
+
+	p.cycles = 0
+	counted := func(x float64) float64 {
+		p.cycles++
+		return f(x)
+	}
+	fc := counted(c)
+	if !finite(fc) {
+		return Result{Value: fc, ErrorEstimate: math.NaN(), Evaluations: p.cycles}, ErrNonFinite
+	}
+	// about the cube root of the machine epsilon, which balances truncation and rounding errors
+	step := 6e-6 * math.Max(1, math.Abs(c))
+	g := func(x float64) float64 {
+		if x == c {
+			return (counted(c+step) - counted(c-step)) / (2 * step)
+		}
+		return (counted(x) - fc) / (x - c)
+	}
+
+	out := Result{Value: fc * math.Log((b-c)/(c-a))}
+	var err error
+	for _, piece := range [][2]float64{{a, c}, {c, b}} {
+		r, pieceErr := p.integrate(g, piece[0], piece[1])
+		out.Value += r.Value
+		out.ErrorEstimate += r.ErrorEstimate
+		if err == nil {
+			err = pieceErr
+		}
+	}
+	out.Value *= sign
+	out.Evaluations = p.cycles
+	return out, err
+}
+
+// integrate integrates 'g' over [a, b] with 'Integrator', using its error estimate when it provides one.
+func (p *PrincipalValue) integrate(g func(x float64) float64, a, b float64) (Result, error) {
+	if integrator, ok := p.Integrator.(interface {
+		DefiniteIntegralResult(f func(x float64) float64, a, b float64) (Result, error)
+	}); ok {
+		return integrator.DefiniteIntegralResult(g, a, b)
+	}
+	return Result{Value: p.Integrator.DefiniteIntegral(g, a, b), ErrorEstimate: math.NaN()}, nil
+}
+
+func (p *PrincipalValue) handleInput() {
+	if p.Integrator == nil {
+		p.Integrator = &GaussKronrod{}
+	}
+}
@@ -0,0 +1,27 @@
+package integration
+
+import (
+	"errors"
+	"math"
+)
+
+var (
+	// ErrMaxEvaluations is returned when the evaluation budget is exhausted before the requested tolerance is reached.
+	ErrMaxEvaluations = errors.New("integration: evaluation budget exhausted before reaching the tolerance")
+	// ErrNonFinite is returned when the integrand evaluates to NaN or ±Inf.
+	ErrNonFinite = errors.New("integration: integrand returned a non-finite value")
+)
+
+// Result holds the outcome of an integration that estimates its own error.
+//
+// 'Value' is the best estimate of the integral, even when an error is returned alongside it,
+// 'ErrorEstimate' is the estimated absolute error of 'Value' and 'Evaluations' is the number of calls made to the integrand.
+type Result struct {
+	Value         float64
+	ErrorEstimate float64
+	Evaluations   uint
+}
+
+func finite(y float64) bool {
+	return !math.IsNaN(y) && !math.IsInf(y, 0)
+}
@@ -0,0 +1,112 @@
+package integration
+
+import (
+	"github.com/rocas777/kairos"
+	"math"
+)
+
+// Romberg provides a method to calculate the definite integral of a given function using [Romberg's method].
+// It repeatedly halves the step of the [Trapezoid] rule and applies Richardson extrapolation to the successive estimates, building a triangular tableau.
+// When halving the step, the previous function values are reused, so each new row only costs one evaluation per added point.
+// A solution is considered definitive once two successive diagonal entries of the tableau differ by less than Epsilon.
+//
+// If 'Epsilon' is not specified, it defaults to 0.001. If 'Epsilon' is less than 0, a panic is raised.
+//
+// If 'MaxDepth' is not specified, it defaults to 20, which corresponds to 2^19 subintervals on the last row.
+//
+// [Romberg's method]: https://en.wikipedia.org/wiki/Romberg%27s_method
+type Romberg struct {
+	Epsilon  float64
+	MaxDepth uint
+	cycles   uint
+	tableau  [][]float64
+}
+
+// NewRomberg creates and returns a pointer to a new [Romberg] instance with the specified value of 'epsilon'.
+//
+// If 'epsilon' is below 0, a panic is raised.
+func NewRomberg(epsilon float64) *Romberg {
+	return &Romberg{Epsilon: epsilon}
+}
+
+// Cycles returns the depth of the tableau built by the last integration, that is, its number of rows.
+func (r *Romberg) Cycles() uint {
+	return r.cycles
+}
+
+// Tableau returns a copy of the tableau built by the last integration.
+// Row 'k' holds k+1 entries: the Trapezoid rule estimate with 2^k subintervals followed by its successive Richardson extrapolations.
+func (r *Romberg) Tableau() [][]float64 {
+	out := make([][]float64, len(r.tableau))
+	for i, row := range r.tableau {
+		out[i] = append([]float64(nil), row...)
+	}
+	return out
+}
+
+// DefiniteIntegral calculates the definite integral of the given function 'f' using Romberg's method.
+// Each new row of the tableau halves the step of the Trapezoidal Rule, and is then extrapolated with the previous row.
+// The last diagonal entry of the tableau is returned once it is within 'Epsilon' of the previous one, or when 'MaxDepth' is reached.
+//
+// The function 'f' represents the integrand, and 'a' and 'b' define the integration interval.
+// The result is returned as a float64 representing the calculated definite integral.
+func (r *Romberg) DefiniteIntegral(f func(x float64) float64, a, b float64) float64 {
+	r.handleInput()
+
+	trapezoid := NewTrapezoid(1)
+	r.tableau = [][]float64{{trapezoid.DefiniteIntegral(f, a, b)}}
+	r.cycles = 1
+	for n := uint(1); r.cycles < r.MaxDepth; n *= 2 {
+		previous := r.tableau[r.cycles-1]
+		row := make([]float64, r.cycles+1)
+		row[0] = trapezoid.halve(f, a, b, previous[0], n)
+		factor := 1.0
+		for j := 1; j < len(row); j++ {
+			factor *= 4
+			row[j] = row[j-1] + (row[j-1]-previous[j-1])/(factor-1)
+		}
+		r.tableau = append(r.tableau, row)
+		r.cycles++
+		if r.cycles > rombergMinDepth && math.Abs(row[len(row)-1]-previous[len(previous)-1]) < r.Epsilon {
+			break
+		}
+	}
+	last := r.tableau[len(r.tableau)-1]
+	return last[len(last)-1]
+}
+
+// AntiDerivative calculates the approximate antiderivative of the given function 'f' using Romberg's method.
+// It samples the antiderivative at 'samples' points within the interval [a, b].
+// The antiderivative is the integral from 0 to each sampled point 'x'. It integrates from 0 to 'a' once, then only the panel between
+// each sample and the previous one, with its share of 'Epsilon', so the cost grows linearly with the number of samples.
+// [Romberg.Cycles] and [Romberg.Tableau] then describe the integration of the last panel.
+//
+// If 'samples' is less than 2, it defaults to 2.
+//
+// The function 'f' represents the integrand, and 'a' and 'b' define the integration interval.
+// The result is returned as a slice of [kairos.Pair] representing the sampled points and their corresponding antiderivative values.
+func (r *Romberg) AntiDerivative(f func(x float64) float64, a, b float64, samples uint) []kairos.Pair {
+	r.handleInput()
+	if samples < 2 {
+		samples = 2
+	}
+	panel := &Romberg{Epsilon: r.Epsilon * panelShare(samples), MaxDepth: r.MaxDepth}
+	out, _ := antiDerivative(r.DefiniteIntegral, panel.DefiniteIntegral, f, a, b, samples)
+	r.cycles, r.tableau = panel.cycles, panel.tableau
+	return out
+}
+
+func (r *Romberg) handleInput() {
+	if r.Epsilon == 0 {
+		r.Epsilon = 0.001
+	} else if r.Epsilon < 0 {
+		panic("Romberg struct value of Epsilon should be higher than 0")
+	}
+	if r.MaxDepth == 0 {
+		r.MaxDepth = 20
+	}
+}
+
+// rombergMinDepth is the number of rows built before checking for convergence,
+// which prevents the coarse first estimates from agreeing by chance on periodic functions.
+const rombergMinDepth = 3
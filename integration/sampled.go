@@ -0,0 +1,120 @@
+package integration
+
+import "github.com/rocas777/kairos"
+
+// SampleRule selects the rule used by [SampledIntegral] and [CumulativeSampledIntegral] to integrate sampled data.
+type SampleRule int
+
+const (
+	// TrapezoidSamples joins successive samples with straight lines.
+	TrapezoidSamples SampleRule = iota
+	// SimpsonSamples joins successive pairs of intervals with parabolas, which is the Simpson 1/3 rule generalized to irregular spacing.
+	// When the number of intervals is odd, the last interval uses the parabola through the last three samples.
+	SimpsonSamples
+	// SplineSamples integrates the natural cubic spline that interpolates the samples.
+	SplineSamples
+)
+
+// SampledIntegral calculates the definite integral of a function known only through the samples in 'data', from the first to the last sample,
+// using the given 'rule'. The samples may be unevenly spaced, which makes it possible, for example, to integrate measurements or the output
+// of a RangeDerivative.
+//
+// The samples must be sorted by strictly increasing X, otherwise a panic is raised. With fewer than 2 samples the integral is 0.
+// [SimpsonSamples] and [SplineSamples] fall back to [TrapezoidSamples] when only 2 samples are given.
+func SampledIntegral(data []kairos.Pair, rule SampleRule) float64 {
+	cumulative := CumulativeSampledIntegral(data, rule)
+	if len(cumulative) == 0 {
+		return 0
+	}
+	return cumulative[len(cumulative)-1].Y
+}
+
+// CumulativeSampledIntegral calculates the running integral of a function known only through the samples in 'data', using the given 'rule'.
+// The result holds, for each sample, its X and the integral from the first sample up to it, so the first value is always 0.
+//
+// The samples must be sorted by strictly increasing X, otherwise a panic is raised.
+func CumulativeSampledIntegral(data []kairos.Pair, rule SampleRule) []kairos.Pair {
+	for i := 1; i < len(data); i++ {
+		if data[i].X <= data[i-1].X {
+			panic("sampled data should be sorted by strictly increasing X")
+		}
+	}
+	out := make([]kairos.Pair, len(data))
+	for i := range data {
+		out[i].X = data[i].X
+	}
+	if len(data) < 2 {
+		return out
+	}
+	if len(data) == 2 {
+		rule = TrapezoidSamples
+	}
+
+	switch rule {
+	case SimpsonSamples:
+		for i := 0; i+1 < len(data); i += 2 {
+			// the last interval borrows the previous sample when it is left alone
+			p := i
+			if i+2 >= len(data) {
+				p = i - 1
+			}
+			out[i+1].Y = out[i].Y + parabolaIntegral(data[p], data[p+1], data[p+2], data[i].X, data[i+1].X)
+			if i+2 < len(data) {
+				out[i+2].Y = out[i].Y + parabolaIntegral(data[p], data[p+1], data[p+2], data[i].X, data[i+2].X)
+			}
+		}
+	case SplineSamples:
+		m := naturalSpline(data)
+		for i := 1; i < len(data); i++ {
+			h := data[i].X - data[i-1].X
+			out[i].Y = out[i-1].Y + h*(data[i-1].Y+data[i].Y)/2 - h*h*h*(m[i-1]+m[i])/24
+		}
+	default:
+		for i := 1; i < len(data); i++ {
+			out[i].Y = out[i-1].Y + (data[i].X-data[i-1].X)*(data[i-1].Y+data[i].Y)/2
+		}
+	}
+	return out
+}
+
+// parabolaIntegral integrates over [from, to] the parabola that goes through p0, p1 and p2.
+// The parabola is written in Newton form around p0.X to avoid cancellation.
+func parabolaIntegral(p0, p1, p2 kairos.Pair, from, to float64) float64 {
+	h0 := p1.X - p0.X
+	d1 := (p1.Y - p0.Y) / h0
+	d2 := ((p2.Y-p1.Y)/(p2.X-p1.X) - d1) / (p2.X - p0.X)
+	// antiderivative of p(t) = y0 + d1·t + d2·t·(t - h0), with t = x - p0.X
+	antiDerivative := func(t float64) float64 {
+		return p0.Y*t + d1*t*t/2 + d2*(t*t*t/3-h0*t*t/2)
+	}
+	return antiDerivative(to-p0.X) - antiDerivative(from-p0.X)
+}
+
+// naturalSpline returns the second derivatives of the natural cubic spline interpolating 'data' at each sample,
+// solving the tridiagonal system with the Thomas algorithm.
+func naturalSpline(data []kairos.Pair) []float64 {
+	n := len(data)
+	m := make([]float64, n)
+	if n < 3 {
+		return m
+	}
+	// diagonal and right-hand side of the system for the inner samples, after forward elimination
+	diagonal := make([]float64, n)
+	rhs := make([]float64, n)
+	for i := 1; i < n-1; i++ {
+		h0 := data[i].X - data[i-1].X
+		h1 := data[i+1].X - data[i].X
+		diagonal[i] = 2 * (h0 + h1)
+		rhs[i] = 6 * ((data[i+1].Y-data[i].Y)/h1 - (data[i].Y-data[i-1].Y)/h0)
+		if i > 1 {
+			factor := h0 / diagonal[i-1]
+			diagonal[i] -= factor * h0
+			rhs[i] -= factor * rhs[i-1]
+		}
+	}
+	for i := n - 2; i >= 1; i-- {
+		h1 := data[i+1].X - data[i].X
+		m[i] = (rhs[i] - h1*m[i+1]) / diagonal[i]
+	}
+	return m
+}
@@ -1,12 +1,3 @@
-// Package integration provides utilities for numerical integration of functions.
-// It includes several methods for calculating definite integrals, such as the Trapezoidal Rule,
-// Simpson's 1/3 Rule, Simpson's 3/8 Rule, and adaptive Simpson integration.
-// Users can choose the appropriate method based on the precision and efficiency requirements
-// of their mathematical analysis.
-//   - [Trapezoid]
-//   - [Simpson_1_3]
-//   - [Simpson_1_3]
-//   - [SimpsonAdaptive]
 package integration
 
 import "github.com/rocas777/kairos"
@@ -59,27 +50,15 @@ func (s *Simpson_1_3) DefiniteIntegral(f func(x float64) float64, a, b float64)
 
 // AntiDerivative calculates the approximate antiderivative of the given function 'f' using the Simpson 1/3 rule.
 // It samples the antiderivative at 'samples' points within the interval [a, b].
-// The antiderivative is computed by performing the Simpson 1/3 rule integration from 0 to each sampled point 'x'.
+// The antiderivative is the integral from 0 to each sampled point 'x', computed cumulatively with [Simpson_1_3.CumulativeIntegral],
+// so each sample only costs the integration of the panel since the previous one.
 //
 // If 'samples' is less than 2, it defaults to 2.
 //
 // The function 'f' represents the integrand, and 'a' and 'b' define the integration interval.
 // The result is returned as a slice of [kairos.Pair] representing the sampled points and their corresponding antiderivative values.
 func (s *Simpson_1_3) AntiDerivative(f func(x float64) float64, a, b float64, samples uint) []kairos.Pair {
-	s.handleInput()
-	if samples < 2 {
-		samples = 2
-	}
-
-	y := 0.0
-	out := make([]kairos.Pair, samples)
-	sampleH := (b - a) / float64(samples-1)
-	for i := 0; i < int(samples); i++ {
-		x := a + float64(i)*sampleH
-		y = s.DefiniteIntegral(f, 0, x)
-		out[i] = kairos.Pair{X: x, Y: y}
-	}
-	return out
+	return s.CumulativeIntegral(f, a, b, samples, 0, 0)
 }
 
 func (s *Simpson_1_3) handleInput() {
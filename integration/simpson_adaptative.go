@@ -47,27 +47,15 @@ func (s *SimpsonAdaptive) DefiniteIntegral(f func(x float64) float64, a, b float
 
 // AntiDerivative calculates the approximate antiderivative of the given function 'f' using adaptive Simpson integration.
 // It samples the antiderivative at 'samples' points within the interval [a, b].
-// The antiderivative is computed by performing adaptive Simpson integration from 0 to each sampled point 'x'.
+// The antiderivative is the integral from 0 to each sampled point 'x', computed cumulatively with [SimpsonAdaptive.CumulativeIntegral],
+// so each sample only costs the integration of the panel since the previous one.
 //
 // If 'samples' is less than 2, it defaults to 2.
 //
 // The function 'f' represents the integrand, and 'a' and 'b' define the integration interval.
 // The result is returned as a slice of [kairos.Pair] representing the sampled points and their corresponding antiderivative values.
 func (s *SimpsonAdaptive) AntiDerivative(f func(x float64) float64, a, b float64, samples uint) []kairos.Pair {
-	s.handleInput()
-	if samples < 2 {
-		samples = 2
-	}
-
-	y := 0.0
-	out := make([]kairos.Pair, samples)
-	sampleH := (b - a) / float64(samples-1)
-	for i := 0; i < int(samples); i++ {
-		x := a + float64(i)*sampleH
-		y = s.DefiniteIntegral(f, 0, x)
-		out[i] = kairos.Pair{X: x, Y: y}
-	}
-	return out
+	return s.CumulativeIntegral(f, a, b, samples, 0, 0)
 }
 
 func (s *SimpsonAdaptive) Cycles() uint {
@@ -0,0 +1,142 @@
+package integration
+
+import (
+	"github.com/rocas777/kairos"
+	"math"
+)
+
+// TanhSinh provides a method to calculate the definite integral of a given function using [tanh-sinh] (double exponential) quadrature.
+// The substitution x = tanh(π/2·sinh(t)) maps the interval onto the whole real line, where the transformed integrand decays double exponentially,
+// so the trapezoidal rule on t converges very quickly. The nodes cluster towards the endpoints without ever reaching them, which makes this method
+// well suited for integrable singularities at the endpoints, such as 1/sqrt(x) or log(x).
+//
+// The step on t is halved at each level, reusing all previous evaluations, until two successive estimates differ by less than Epsilon
+// or 'MaxLevel' is reached.
+//
+// If 'Epsilon' is not specified, it defaults to 1e-8. If 'Epsilon' is less than 0, a panic is raised.
+//
+// If 'MaxLevel' is not specified, it defaults to 10.
+//
+// [tanh-sinh]: https://en.wikipedia.org/wiki/Tanh-sinh_quadrature
+type TanhSinh struct {
+	Epsilon  float64
+	MaxLevel uint
+	cycles   uint
+}
+
+// NewTanhSinh creates and returns a pointer to a new [TanhSinh] instance with the specified value of 'epsilon'.
+//
+// If 'epsilon' is below 0, a panic is raised.
+func NewTanhSinh(epsilon float64) *TanhSinh {
+	return &TanhSinh{Epsilon: epsilon}
+}
+
+// Cycles returns the number of evaluations of the integrand made by the last integration.
+func (s *TanhSinh) Cycles() uint {
+	return s.cycles
+}
+
+// DefiniteIntegral calculates the definite integral of the given function 'f' using tanh-sinh quadrature.
+// The function is never evaluated at 'a' or 'b'.
+//
+// The function 'f' represents the integrand, and 'a' and 'b' define the integration interval.
+// The result is returned as a float64 representing the calculated definite integral.
+func (s *TanhSinh) DefiniteIntegral(f func(x float64) float64, a, b float64) float64 {
+	r, _ := s.DefiniteIntegralResult(f, a, b)
+	return r.Value
+}
+
+// DefiniteIntegralResult works like [TanhSinh.DefiniteIntegral] but returns a [Result] holding the integral, the difference between
+// the last two levels as its estimated error and the number of evaluations. The error is [ErrMaxEvaluations] if 'MaxLevel' is reached
+// before the estimates agree within 'Epsilon' and [ErrNonFinite] if 'f' returns NaN or ±Inf.
+func (s *TanhSinh) DefiniteIntegralResult(f func(x float64) float64, a, b float64) (Result, error) {
+	s.handleInput()
+
+	s.cycles = 0
+	half := (b - a) / 2
+	nonFinite := false
+	// pair evaluates the weighted contribution of the nodes at t and -t
+	pair := func(t float64) float64 {
+		u := math.Pi / 2 * math.Sinh(t)
+		e := math.Exp(-2 * u)
+		// distance of the nodes to the endpoints, computed without cancellation
+		dx := half * 2 * e / (1 + e)
+		w := math.Pi / 2 * math.Cosh(t) * 4 * e / ((1 + e) * (1 + e))
+		out := 0.0
+		if left := a + dx; left != a {
+			s.cycles++
+			out += f(left)
+		}
+		if right := b - dx; right != b {
+			s.cycles++
+			out += f(right)
+		}
+		if !finite(out) {
+			nonFinite = true
+		}
+		return w * half * out
+	}
+
+	h := 1.0
+	s.cycles++
+	sum := math.Pi / 2 * half * f((a+b)/2)
+	for t := h; t <= tanhSinhMax; t += h {
+		sum += pair(t)
+	}
+	estimate := h * sum
+	if nonFinite || !finite(estimate) {
+		return Result{Value: estimate, ErrorEstimate: math.Inf(1), Evaluations: s.cycles}, ErrNonFinite
+	}
+	err := math.Inf(1)
+	for level := uint(1); level <= s.MaxLevel; level++ {
+		h /= 2
+		for t := h; t <= tanhSinhMax; t += 2 * h {
+			sum += pair(t)
+		}
+		next := h * sum
+		err = math.Abs(next - estimate)
+		estimate = next
+		if nonFinite || !finite(estimate) {
+			return Result{Value: estimate, ErrorEstimate: math.Inf(1), Evaluations: s.cycles}, ErrNonFinite
+		}
+		if err < s.Epsilon {
+			return Result{Value: estimate, ErrorEstimate: err, Evaluations: s.cycles}, nil
+		}
+	}
+	return Result{Value: estimate, ErrorEstimate: err, Evaluations: s.cycles}, ErrMaxEvaluations
+}
+
+// AntiDerivative calculates the approximate antiderivative of the given function 'f' using tanh-sinh quadrature.
+// It samples the antiderivative at 'samples' points within the interval [a, b].
+// The antiderivative is the integral from 0 to each sampled point 'x'. It integrates from 0 to 'a' once, then only the panel between
+// each sample and the previous one, with its share of 'Epsilon', so the cost grows linearly with the number of samples.
+// [TanhSinh.Cycles] then reports the total number of evaluations of 'f'.
+//
+// If 'samples' is less than 2, it defaults to 2.
+//
+// The function 'f' represents the integrand, and 'a' and 'b' define the integration interval.
+// The result is returned as a slice of [kairos.Pair] representing the sampled points and their corresponding antiderivative values.
+func (s *TanhSinh) AntiDerivative(f func(x float64) float64, a, b float64, samples uint) []kairos.Pair {
+	s.handleInput()
+	if samples < 2 {
+		samples = 2
+	}
+	panel := &TanhSinh{Epsilon: s.Epsilon * panelShare(samples), MaxLevel: s.MaxLevel}
+	out, evaluations := antiDerivative(s.DefiniteIntegral, panel.DefiniteIntegral, f, a, b, samples)
+	s.cycles = evaluations
+	return out
+}
+
+func (s *TanhSinh) handleInput() {
+	if s.Epsilon == 0 {
+		s.Epsilon = 1e-8
+	} else if s.Epsilon < 0 {
+		panic("TanhSinh struct value of Epsilon should be higher than 0")
+	}
+	if s.MaxLevel == 0 {
+		s.MaxLevel = 10
+	}
+}
+
+// tanhSinhMax truncates the t axis, beyond it the weights fall below 1e-58.
+const tanhSinhMax = 4.5
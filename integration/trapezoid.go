@@ -42,29 +42,29 @@ func (t *Trapezoid) DefiniteIntegral(f func(x float64) float64, a, b float64) fl
 	return out
 }
 
+// halve calculates the Trapezoidal Rule estimate with 2n subintervals from 'previous', the estimate with n subintervals over [a, b].
+// Only the n new midpoints are evaluated, so the previous evaluations of 'f' are reused.
+func (t *Trapezoid) halve(f func(x float64) float64, a, b, previous float64, n uint) float64 {
+	h := (b - a) / float64(n)
+	out := 0.0
+	for i := 0; i < int(n); i++ {
+		t.cycles++
+		out += f(a + (float64(i)+0.5)*h)
+	}
+	return previous/2 + out*h/2
+}
+
 // AntiDerivative calculates the approximate antiderivative of the given function 'f' using the Trapezoidal Rule.
 // It samples the antiderivative at 'samples' points within the interval [a, b].
-// The antiderivative is computed by performing the Trapezoidal Rule integration from 0 to each sampled point 'x'.
+// The antiderivative is the integral from 0 to each sampled point 'x', computed cumulatively with [Trapezoid.CumulativeIntegral],
+// so each sample only costs the integration of the panel since the previous one.
 //
 // If 'samples' is less than 2, it defaults to 2.
 //
 // The function 'f' represents the integrand, and 'a' and 'b' define the integration interval.
 // The result is returned as a slice of [kairos.Pair] representing the sampled points and their corresponding antiderivative values.
 func (t *Trapezoid) AntiDerivative(f func(x float64) float64, a, b float64, samples uint) []kairos.Pair {
-	t.handleInput()
-	if samples < 2 {
-		samples = 2
-	}
-
-	y := 0.0
-	out := make([]kairos.Pair, samples)
-	sampleH := (b - a) / float64(samples-1)
-	for i := 0; i < int(samples); i++ {
-		x := a + float64(i)*sampleH
-		y = t.DefiniteIntegral(f, 0, x)
-		out[i] = kairos.Pair{X: x, Y: y}
-	}
-	return out
+	return t.CumulativeIntegral(f, a, b, samples, 0, 0)
 }
 
 func (t *Trapezoid) handleInput() {
@@ -0,0 +1,31 @@
+// Package fourier provides the fast Fourier transform shared by the kairos subpackages.
+package fourier
+
+import (
+	"math"
+	"math/bits"
+	"math/cmplx"
+)
+
+// FFT replaces 'values', whose length must be a power of 2, by their discrete Fourier transform Σ values[j]·e^(-2πijk/N),
+// using the iterative radix-2 Cooley-Tukey algorithm.
+func FFT(values []complex128) {
+	n := len(values)
+	shift := bits.UintSize - bits.Len(uint(n-1))
+	for i := range values {
+		if j := int(bits.Reverse(uint(i)) >> shift); n > 1 && i < j {
+			values[i], values[j] = values[j], values[i]
+		}
+	}
+	for size := 2; size <= n; size *= 2 {
+		for start := 0; start < n; start += size {
+			for i := 0; i < size/2; i++ {
+				// the twiddle factors are computed directly, as repeated products would accumulate rounding errors
+				w := cmplx.Rect(1, -2*math.Pi*float64(i)/float64(size))
+				even, odd := values[start+i], w*values[start+i+size/2]
+				values[start+i] = even + odd
+				values[start+i+size/2] = even - odd
+			}
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package fourier
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+func TestFFT(t *testing.T) {
+	for _, n := range []int{1, 2, 8, 64} {
+		values := make([]complex128, n)
+		for j := range values {
+			values[j] = complex(math.Sin(float64(j)), math.Cos(3*float64(j)))
+		}
+		want := make([]complex128, n)
+		for k := range want {
+			for j, v := range values {
+				want[k] += v * cmplx.Rect(1, -2*math.Pi*float64(j*k)/float64(n))
+			}
+		}
+		FFT(values)
+		for k := range want {
+			if cmplx.Abs(values[k]-want[k]) > 1e-12 {
+				t.Fatalf("n = %d: Got: %v at %d, wanted: %v", n, values[k], k, want[k])
+			}
+		}
+	}
+}
@@ -1,22 +1,37 @@
 // Package kairos provides utilities for mathematical computations and analyses related to calculus and equations.
-// It consists of three subpackages: integration, equation, and differentiation.
+// It consists of four subpackages: integration, equation, differentiation and autodiff.
 //
 // # Integration Package:
 //
 // The integration package offers methods to calculate definite integrals using different techniques.
-// It includes the Trapezoidal Rule, Simpson's 1/3 Rule, Simpson's 3/8 Rule, and adaptive Simpson integration.
+// It includes Newton-Cotes rules (the Trapezoidal Rule, Simpson's 1/3 Rule and Simpson's 3/8 Rule), extrapolated and adaptive methods
+// (Romberg, adaptive Simpson and adaptive Gauss-Kronrod integration), Gauss-Legendre, Clenshaw-Curtis and tanh-sinh quadrature, weighted Gaussian quadrature (Hermite, Laguerre, Jacobi and Chebyshev), improper integrals over infinite intervals,
+// Filon and Levin methods for highly oscillatory integrals and Cauchy principal values.
+// Multidimensional integrals over boxes are supported through tensor-product rules, adaptive Genz-Malik cubature
+// and Monte Carlo or quasi-Monte Carlo integration. Sampled data, given as [Pair] slices, can be integrated as well,
+// and running integrals can be sampled cumulatively in linear time.
 //
 // # Equation Package:
 //
 // The equation package provides methods to find the zero of a given function using various root-finding algorithms.
-// The supported methods are Bisection, FalsePosition, NewtonRaphson, and Secant.
+// The supported methods are Bisection, Brent, FalsePosition, NewtonRaphson, and Secant.
 //
 // # Differentiation Package:
 //
 // The differentiation package offers methods to calculate derivatives of functions.
 // It supports the calculation of the first derivative using two algorithms: Simple (based on the regular definition)
-// and Symmetric (based on the symmetric definition). Additionally, it provides the ability to calculate arbitrary
-// order derivatives using the HigherOrder method.
+// and Symmetric (based on the symmetric definition), as well as the complex-step method and Ridders' method, which extrapolates symmetric differences
+// and returns an error estimate. Additionally, it provides the ability to calculate arbitrary
+// order derivatives using the HigherOrder method, or finite-difference stencils of any order and accuracy using the FiniteDifference method.
+// Taylor coefficients of high order are computed with the Cauchy integral formula by the Taylor method.
+// Gradients, Jacobians and Hessians of functions of several variables are provided by the Multivariate method,
+// and noisy sampled data can be differentiated with Savitzky-Golay filters or total-variation regularization.
+//
+// # Autodiff Package:
+//
+// The autodiff package provides exact derivatives through forward-mode automatic differentiation with dual numbers,
+// and gradients of functions of many inputs through a reverse-mode tape.
+// Functions written against its Dual type can also be handed to the Newton-Raphson root finder without a hand-written derivative.
 //
 // The [kairos] package aims to assist users in performing mathematical computations with a focus on calculus and equation solving.
 // It provides flexibility in choosing different methods depending on the specific requirements of the user's mathematical analysis.